@@ -0,0 +1,99 @@
+// Copyright 2020 Julien Cretel (jub0bs). All rights reserved.
+// Use of this source code is governed by a BSD 3-clause
+// license that can be found in the LICENSE file.
+
+package bimap
+
+// An AnnotatedBimap is a Bimap in which each key-value pair may
+// carry an annotation. An annotation lives as long as the pair it
+// is attached to: removing a pair, whether explicitly or as a side
+// effect of storing another pair, also removes its annotation.
+//
+// The zero value for AnnotatedBimap is empty and ready for use.
+// An AnnotatedBimap must not be copied after first use.
+type AnnotatedBimap[K, V, A comparable] struct {
+	bi          Bimap[K, V]
+	annotations map[K]A
+}
+
+// NewAnnotated returns a new, empty AnnotatedBimap.
+func NewAnnotated[K, V, A comparable]() *AnnotatedBimap[K, V, A] {
+	return &AnnotatedBimap[K, V, A]{}
+}
+
+// Store creates a key-value pair, as Bimap.Store does, and returns
+// whether or not the operation was successful. The annotations of
+// the pairs that the operation removes, including the one
+// previously associated with the given key (if any), are dropped;
+// storing a pair that is already present leaves its annotation
+// untouched.
+func (ab *AnnotatedBimap[K, V, A]) Store(key K, value V) bool {
+	unchanged := ab.bi.ContainsPair(key, value)
+	evicted, taken := ab.bi.LoadKey(value)
+	if !ab.bi.Store(key, value) {
+		return false
+	}
+	if !unchanged {
+		delete(ab.annotations, key)
+		if taken {
+			delete(ab.annotations, evicted)
+		}
+	}
+	return true
+}
+
+// LoadValue returns the value stored in the AnnotatedBimap for a
+// key, as Bimap.LoadValue does.
+func (ab *AnnotatedBimap[K, V, A]) LoadValue(k K) (V, bool) {
+	return ab.bi.LoadValue(k)
+}
+
+// LoadKey returns the key stored in the AnnotatedBimap for a
+// value, as Bimap.LoadKey does.
+func (ab *AnnotatedBimap[K, V, A]) LoadKey(v V) (K, bool) {
+	return ab.bi.LoadKey(v)
+}
+
+// DeleteByKey deletes the key-value pair involving the given key,
+// along with its annotation.
+func (ab *AnnotatedBimap[K, V, A]) DeleteByKey(k K) {
+	ab.bi.DeleteByKey(k)
+	delete(ab.annotations, k)
+}
+
+// DeleteByValue deletes the key-value pair involving the given
+// value, along with its annotation.
+func (ab *AnnotatedBimap[K, V, A]) DeleteByValue(v V) {
+	if k, exists := ab.bi.LoadKey(v); exists {
+		ab.bi.DeleteByValue(v)
+		delete(ab.annotations, k)
+	}
+}
+
+// Size returns the number of key-value pairs in the AnnotatedBimap.
+func (ab *AnnotatedBimap[K, V, A]) Size() int {
+	return ab.bi.Size()
+}
+
+// SetAnnotation attaches an annotation to the key-value pair
+// involving the given key, replacing its previous annotation
+// (if any), and reports whether such a pair exists.
+func (ab *AnnotatedBimap[K, V, A]) SetAnnotation(k K, a A) bool {
+	if _, exists := ab.bi.LoadValue(k); !exists {
+		return false
+	}
+	if ab.annotations == nil {
+		ab.annotations = make(map[K]A)
+	}
+	ab.annotations[k] = a
+	return true
+}
+
+// GetAnnotation returns the annotation attached to the key-value
+// pair involving the given key, or the zero value of the A type if
+// no annotation is present.
+// The ok result indicates whether an annotation was found.
+func (ab *AnnotatedBimap[K, V, A]) GetAnnotation(k K) (A, bool) {
+	a, ok := ab.annotations[k]
+	return a, ok
+}
@@ -0,0 +1,49 @@
+package bimap
+
+import "testing"
+
+func TestThatAnnotationsRequireAPair(t *testing.T) {
+	ab := NewAnnotated[int, string, string]()
+	if ok := ab.SetAnnotation(1, "note"); ok {
+		t.Errorf("got %t; want %t", ok, false)
+	}
+	if a, ok := ab.GetAnnotation(1); ok {
+		t.Errorf("got %q, %t; want %q, %t", a, ok, "", false)
+	}
+}
+
+func TestThatAnnotationsTrackThePairLifecycle(t *testing.T) {
+	ab := new(AnnotatedBimap[int, string, string])
+	ab.Store(1, "one")
+	ab.Store(2, "two")
+	ab.Store(3, "three")
+	ab.Store(4, "four")
+	for k := 1; k <= 4; k++ {
+		if ok := ab.SetAnnotation(k, "note"); !ok {
+			t.Fatalf("SetAnnotation(%d, ...) = %t; want %t", k, ok, true)
+		}
+	}
+	ab.Store(1, "one") // no-op
+	if a, ok := ab.GetAnnotation(1); !ok || a != "note" {
+		t.Errorf("after no-op store: got %q, %t; want %q, %t", a, ok, "note", true)
+	}
+	ab.Store(5, "two") // evicts 2:"two"
+	if a, ok := ab.GetAnnotation(2); ok {
+		t.Errorf("after eviction: got %q, %t; want %q, %t", a, ok, "", false)
+	}
+	ab.Store(1, "uno") // replaces 1:"one"
+	if a, ok := ab.GetAnnotation(1); ok {
+		t.Errorf("after reassignment: got %q, %t; want %q, %t", a, ok, "", false)
+	}
+	ab.DeleteByKey(3)
+	if a, ok := ab.GetAnnotation(3); ok {
+		t.Errorf("after DeleteByKey: got %q, %t; want %q, %t", a, ok, "", false)
+	}
+	ab.DeleteByValue("four")
+	if a, ok := ab.GetAnnotation(4); ok {
+		t.Errorf("after DeleteByValue: got %q, %t; want %q, %t", a, ok, "", false)
+	}
+	if size := ab.Size(); size != 2 {
+		t.Errorf("ab.Size() = %d; want %d", size, 2)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 Julien Cretel (jub0bs). All rights reserved.
+// Use of this source code is governed by a BSD 3-clause
+// license that can be found in the LICENSE file.
+
+package bimap
+
+// A Backing is a map-like data structure in which a Bimap stores
+// its key-value pairs in one direction. Like a builtin map, a
+// Backing must tolerate calls to Delete during calls to Range.
+type Backing[K, V comparable] interface {
+	// Load returns the value associated with k, if any.
+	Load(k K) (V, bool)
+	// Store associates v with k.
+	Store(k K, v V)
+	// Delete removes the entry for k, if any.
+	Delete(k K)
+	// Len returns the number of entries.
+	Len() int
+	// Range calls f sequentially for each entry; if f returns
+	// false, Range stops the iteration.
+	Range(f func(K, V) bool)
+}
+
+// A BackingFactory produces the backings in which a Bimap stores
+// its key-value pairs. Backings produced by a BackingFactory must
+// be empty and must not be shared with other bimaps.
+type BackingFactory[K, V comparable] interface {
+	// NewForward returns a Backing that maps keys to values,
+	// with room for approximately sizeHint entries.
+	NewForward(sizeHint int) Backing[K, V]
+	// NewInverse returns a Backing that maps values to keys,
+	// with room for approximately sizeHint entries.
+	NewInverse(sizeHint int) Backing[V, K]
+}
+
+// SetBacking makes the Bimap store its key-value pairs in the
+// backings that factory produces, moving any pairs already present
+// to such backings; a nil factory means builtin maps. Unlike
+// NewWithBacking, SetBacking can be applied to a Bimap returned by
+// any constructor, e.g. NewValidated. SetBacking panics if bi is nil.
+func (bi *Bimap[K, V]) SetBacking(factory BackingFactory[K, V]) {
+	bi.mustBeNonNil("SetBacking")
+	bi.factory = factory
+	if bi.forward != nil {
+		bi.reallocate()
+	}
+}
+
+// newBackings returns new, empty backings for the Bimap.
+func (bi *Bimap[K, V]) newBackings(sizeHint int) (Backing[K, V], Backing[V, K]) {
+	if bi == nil || bi.factory == nil {
+		return make(builtinMap[K, V], sizeHint), make(builtinMap[V, K], sizeHint)
+	}
+	return bi.factory.NewForward(sizeHint), bi.factory.NewInverse(sizeHint)
+}
+
+// fwd returns the Bimap's forward backing, or an empty Backing if
+// the Bimap is nil or hasn't been initialised yet.
+func (bi *Bimap[K, V]) fwd() Backing[K, V] {
+	if bi == nil || bi.forward == nil {
+		return builtinMap[K, V](nil)
+	}
+	return bi.forward
+}
+
+// inv returns the Bimap's inverse backing, or an empty Backing if
+// the Bimap is nil or hasn't been initialised yet.
+func (bi *Bimap[K, V]) inv() Backing[V, K] {
+	if bi == nil || bi.inverse == nil {
+		return builtinMap[V, K](nil)
+	}
+	return bi.inverse
+}
+
+// builtinMap is the default Backing.
+type builtinMap[K, V comparable] map[K]V
+
+func (m builtinMap[K, V]) Load(k K) (V, bool) {
+	v, ok := m[k]
+	return v, ok
+}
+
+func (m builtinMap[K, V]) Store(k K, v V) {
+	m[k] = v
+}
+
+func (m builtinMap[K, V]) Delete(k K) {
+	delete(m, k)
+}
+
+func (m builtinMap[K, V]) Len() int {
+	return len(m)
+}
+
+func (m builtinMap[K, V]) Range(f func(K, V) bool) {
+	for k, v := range m {
+		if !f(k, v) {
+			return
+		}
+	}
+}
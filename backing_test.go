@@ -0,0 +1,216 @@
+package bimap
+
+import "testing"
+
+type mockBacking[K, V comparable] struct {
+	m       map[K]V
+	stores  int
+	deletes int
+}
+
+func (b *mockBacking[K, V]) Load(k K) (V, bool) {
+	v, ok := b.m[k]
+	return v, ok
+}
+
+func (b *mockBacking[K, V]) Store(k K, v V) {
+	b.stores++
+	b.m[k] = v
+}
+
+func (b *mockBacking[K, V]) Delete(k K) {
+	b.deletes++
+	delete(b.m, k)
+}
+
+func (b *mockBacking[K, V]) Len() int {
+	return len(b.m)
+}
+
+func (b *mockBacking[K, V]) Range(f func(K, V) bool) {
+	for k, v := range b.m {
+		if !f(k, v) {
+			return
+		}
+	}
+}
+
+// miscountedBacking is a faulty Backing whose Len always reports
+// a single entry.
+type miscountedBacking[K, V comparable] struct {
+	*mockBacking[K, V]
+}
+
+func (b miscountedBacking[K, V]) Len() int {
+	return 1
+}
+
+type mockFactory[K, V comparable] struct {
+	forward *mockBacking[K, V]
+	inverse *mockBacking[V, K]
+}
+
+func (f *mockFactory[K, V]) NewForward(sizeHint int) Backing[K, V] {
+	f.forward = &mockBacking[K, V]{m: make(map[K]V, sizeHint)}
+	return f.forward
+}
+
+func (f *mockFactory[K, V]) NewInverse(sizeHint int) Backing[V, K] {
+	f.inverse = &mockBacking[V, K]{m: make(map[V]K, sizeHint)}
+	return f.inverse
+}
+
+func TestThatTheDefaultBackingIsABuiltinMap(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	if _, ok := bi.forward.(builtinMap[int, string]); !ok {
+		t.Errorf("got forward backing of type %T; want builtinMap", bi.forward)
+	}
+	if _, ok := bi.inverse.(builtinMap[string, int]); !ok {
+		t.Errorf("got inverse backing of type %T; want builtinMap", bi.inverse)
+	}
+}
+
+func TestThatABimapStoresItsPairsInTheBackingsOfItsFactory(t *testing.T) {
+	factory := new(mockFactory[int, string])
+	bi := NewWithBacking[int, string](factory)
+	if factory.forward != nil || factory.inverse != nil {
+		t.Fatal("backings unexpectedly created before the first store")
+	}
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	bi.Store(1, "two") // evicts 1:"one" and 2:"two"
+	if v, ok := factory.forward.m[1]; !ok || v != "two" || len(factory.forward.m) != 1 {
+		t.Errorf("got forward backing %v; want map[1:two]", factory.forward.m)
+	}
+	if k, ok := factory.inverse.m["two"]; !ok || k != 1 || len(factory.inverse.m) != 1 {
+		t.Errorf("got inverse backing %v; want map[two:1]", factory.inverse.m)
+	}
+	if factory.forward.stores != 3 || factory.inverse.stores != 3 {
+		const tmpl = "got %d and %d stores; want 3 and 3"
+		t.Errorf(tmpl, factory.forward.stores, factory.inverse.stores)
+	}
+	if v, ok := bi.LoadValue(1); !ok || v != "two" {
+		t.Errorf("got %q, %t; want %q, %t", v, ok, "two", true)
+	}
+	if size := bi.Size(); size != 1 {
+		t.Errorf("bi.Size() = %d; want %d", size, 1)
+	}
+	if got, want := bi.String(), "Bimap[1:two]"; got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+	bi.DeleteByValue("two")
+	if factory.forward.Len() != 0 || factory.inverse.Len() != 0 {
+		t.Errorf("got non-empty backings %v and %v", factory.forward.m, factory.inverse.m)
+	}
+}
+
+func TestThatSetBackingCombinesWithValidators(t *testing.T) {
+	bi := NewNonZero[int, string]()
+	bi.Store(1, "one")
+	factory := new(mockFactory[int, string])
+	bi.SetBacking(factory)
+	if v, ok := factory.forward.m[1]; !ok || v != "one" {
+		t.Errorf("got forward backing %v; want map[1:one]", factory.forward.m)
+	}
+	if bi.Store(0, "zero") {
+		t.Errorf("got %t for a zero key; want %t", true, false)
+	}
+	bi.Store(2, "two")
+	if k, ok := factory.inverse.m["two"]; !ok || k != 2 || factory.inverse.Len() != 2 {
+		t.Errorf("got inverse backing %v; want map[one:1 two:2]", factory.inverse.m)
+	}
+}
+
+func TestThatAutoShrinkRebuildsTheBackingsAfterEnoughDeletions(t *testing.T) {
+	factory := new(mockFactory[int, int])
+	bi := NewWithBacking[int, int](factory)
+	bi.SetAutoShrink(0.5)
+	for i := 0; i < 10; i++ {
+		bi.Store(i, -i)
+	}
+	initial := factory.forward
+	for i := 0; i < 5; i++ {
+		bi.DeleteByKey(i)
+	}
+	if factory.forward != initial {
+		t.Fatal("backings rebuilt too early")
+	}
+	bi.DeleteByValue(-5) // size drops below half the peak size
+	if factory.forward == initial {
+		t.Fatal("backings not rebuilt")
+	}
+	if size := bi.Size(); size != 4 || factory.forward.Len() != 4 || factory.inverse.Len() != 4 {
+		t.Errorf("got sizes %d, %d, %d; want 4, 4, 4", size, factory.forward.Len(), factory.inverse.Len())
+	}
+	for i := 6; i < 10; i++ {
+		if v, ok := bi.LoadValue(i); !ok || v != -i {
+			t.Errorf("got %d, %t; want %d, %t", v, ok, -i, true)
+		}
+	}
+	if !CheckRoundTrip(bi) {
+		t.Error("bimap inconsistent after shrinking")
+	}
+}
+
+func TestThatAutoShrinkIsDisabledByDefault(t *testing.T) {
+	factory := new(mockFactory[int, int])
+	bi := NewWithBacking[int, int](factory)
+	for i := 0; i < 10; i++ {
+		bi.Store(i, -i)
+	}
+	initial := factory.forward
+	for i := 0; i < 9; i++ {
+		bi.DeleteByKey(i)
+	}
+	if factory.forward != initial {
+		t.Error("backings unexpectedly rebuilt")
+	}
+}
+
+func TestThatAutoShrinkWorksAfterUnmarshalBinary(t *testing.T) {
+	src := New[int32, int32]()
+	for i := int32(0); i < 10; i++ {
+		src.Store(i, -i)
+	}
+	data, err := src.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	factory := new(mockFactory[int32, int32])
+	bi := NewWithBacking[int32, int32](factory)
+	bi.SetAutoShrink(0.5)
+	if err := bi.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	initial := factory.forward
+	for i := int32(0); i < 6; i++ {
+		bi.DeleteByKey(i)
+	}
+	if factory.forward == initial {
+		t.Fatal("backings not rebuilt")
+	}
+}
+
+func TestThatAutoShrinkWorksAfterMustFromMap(t *testing.T) {
+	m := make(map[int]int)
+	for i := 0; i < 10; i++ {
+		m[i] = -i
+	}
+	bi := MustFromMap(m)
+	bi.SetAutoShrink(0.5)
+	for i := 0; i < 6; i++ {
+		bi.DeleteByKey(i)
+	}
+	if bi.peak != 4 {
+		t.Errorf("got peak %d; want 4", bi.peak)
+	}
+}
+
+func TestThatOnlyToleratesABackingWhoseLenIsWrong(t *testing.T) {
+	bi := New[int, string]()
+	bi.forward = miscountedBacking[int, string]{&mockBacking[int, string]{m: map[int]string{}}}
+	if p, ok := bi.Only(); ok {
+		t.Errorf("got %v, %t; want %v, %t", p, ok, Pair[int, string]{}, false)
+	}
+}
@@ -6,19 +6,56 @@
 // key and value types.
 package bimap
 
-import "fmt"
+import (
+	"fmt"
+	"iter"
+
+	"golang.org/x/exp/slices"
+)
 
 // A Bimap is a bidirectional map, i.e. an associative data
 // structure in which key-value pairs form a one-to-one
 // correspondence.
 // Both keys and values must be comparable.
-// Loads, stores, and deletes run in amortized constant time.
+// Loads, stores, and deletes run in amortized constant time,
+// unless the Bimap relies on backings (see BackingFactory) that
+// offer weaker guarantees.
 //
 // The zero value for Bimap is empty and ready for use.
 // A Bimap must not be copied after first use.
+//
+// Much like a nil map, a nil *Bimap behaves like an empty Bimap
+// that cannot grow: methods that only read the Bimap or only
+// remove pairs from it work as they would on an empty Bimap,
+// whereas methods that store pairs in it or configure it panic
+// with an explicit message, as documented on each of them.
 type Bimap[K, V comparable] struct {
-	forward map[K]V
-	inverse map[V]K
+	forward    Backing[K, V]        // nil until bi is initialised
+	inverse    Backing[V, K]        // nil until bi is initialised
+	factory    BackingFactory[K, V] // nil means builtin maps
+	keyOK      func(K) bool         // nil means all keys are allowed
+	valOK      func(V) bool         // nil means all values are allowed
+	maxSize    int                  // maximum number of pairs; 0 means unlimited
+	shrink     float64              // auto-shrink ratio; 0 means disabled
+	peak       int                  // peak size since the backings were created
+	hooks      []func(ChangeEvent[K, V])
+	last       lastStored[K, V]
+	journaling bool // whether Store records pairs in journal
+	journal    []Pair[K, V]
+	tombs      map[K]V      // soft-deleted pairs
+	spilled    []Pair[K, V] // pairs rejected by StoreWithOverflow
+}
+
+// lastStored records the most recently stored key-value pair.
+type lastStored[K, V comparable] struct {
+	pair Pair[K, V]
+	ok   bool // whether any pair has been stored
+}
+
+// A Pair is a key-value pair.
+type Pair[K, V comparable] struct {
+	Key   K
+	Value V
 }
 
 // New returns a new, empty Bimap.
@@ -26,32 +63,423 @@ func New[K, V comparable]() *Bimap[K, V] {
 	return &Bimap[K, V]{}
 }
 
+// NewWithBacking returns a new, empty Bimap that stores its
+// key-value pairs in the backings that factory produces rather than
+// in builtin maps. A nil factory means builtin maps. To combine a
+// BackingFactory with validators, see SetBacking.
+func NewWithBacking[K, V comparable](factory BackingFactory[K, V]) *Bimap[K, V] {
+	return &Bimap[K, V]{factory: factory}
+}
+
+// NewNonZero returns a new, empty Bimap that rejects keys and
+// values equal to the zero value of their type.
+// Note that a value is rejected whenever it compares equal to the
+// zero value; for instance, a Bimap[float64, V] returned by
+// NewNonZero rejects negative zero as well as positive zero,
+// and a Bimap whose key type is an interface type rejects nil
+// keys but not keys that hold a typed zero value.
+func NewNonZero[K, V comparable]() *Bimap[K, V] {
+	return NewValidated(isNonZero[K], isNonZero[V])
+}
+
+// NewValidated returns a new, empty Bimap that only allows keys
+// for which keyOK returns true and values for which valOK returns
+// true. A nil function allows everything.
+func NewValidated[K, V comparable](keyOK func(K) bool, valOK func(V) bool) *Bimap[K, V] {
+	return &Bimap[K, V]{keyOK: keyOK, valOK: valOK}
+}
+
+// FromSlices returns a new Bimap that associates keys[i] with
+// values[i] for each index i. It returns an error if keys and
+// values differ in length, if they contain duplicates, or if they
+// contain keys or values for which equality is not reflexive.
+func FromSlices[K, V comparable](keys []K, values []V) (*Bimap[K, V], error) {
+	if len(keys) != len(values) {
+		const tmpl = "bimap: mismatched lengths of keys (%d) and values (%d)"
+		return nil, fmt.Errorf(tmpl, len(keys), len(values))
+	}
+	bi := New[K, V]()
+	forward, inverse := bi.newBackings(len(keys))
+	for i, k := range keys {
+		if err := bi.insertUnique(forward, inverse, k, values[i]); err != nil {
+			return nil, err
+		}
+	}
+	bi.setBackings(forward, inverse)
+	return bi, nil
+}
+
+// MustFromMap returns a new Bimap containing the key-value pairs
+// of m. It panics if m associates the same value with several keys
+// or contains keys or values for which equality is not reflexive.
+// It is intended for initialising package-level variables that
+// hold constant tables.
+func MustFromMap[K, V comparable](m map[K]V) *Bimap[K, V] {
+	bi := New[K, V]()
+	forward, inverse := bi.newBackings(len(m))
+	for k, v := range m {
+		if err := bi.insertUnique(forward, inverse, k, v); err != nil {
+			panic(err.Error())
+		}
+	}
+	bi.setBackings(forward, inverse)
+	return bi
+}
+
+// FromRange returns a new Bimap that associates each integer i in
+// [0, n) with f(i). The ok result is false if f produces duplicate
+// values or values for which equality is not reflexive.
+func FromRange[V comparable](n int, f func(int) V) (*Bimap[int, V], bool) {
+	bi := New[int, V]()
+	forward, inverse := bi.newBackings(max(n, 0))
+	for i := 0; i < n; i++ {
+		if err := bi.insertUnique(forward, inverse, i, f(i)); err != nil {
+			return nil, false
+		}
+	}
+	bi.setBackings(forward, inverse)
+	return bi, true
+}
+
+// FromSeq2WithSize returns a new Bimap containing the key-value
+// pairs yielded by seq. Its backings are preallocated for sizeHint
+// pairs, which avoids rehashing when sizeHint is close to the
+// number of pairs that seq yields. The ok result is false if seq
+// yields duplicate keys, duplicate values, or keys or values for
+// which equality is not reflexive.
+func FromSeq2WithSize[K, V comparable](seq iter.Seq2[K, V], sizeHint int) (*Bimap[K, V], bool) {
+	bi := New[K, V]()
+	forward, inverse := bi.newBackings(max(sizeHint, 0))
+	for k, v := range seq {
+		if err := bi.insertUnique(forward, inverse, k, v); err != nil {
+			return nil, false
+		}
+	}
+	bi.setBackings(forward, inverse)
+	return bi, true
+}
+
+// AssignValues returns a new Bimap that associates keys[i] with
+// gen(keys[i], i) for each index i. The ok result is false if keys
+// contains duplicates, if gen produces duplicate values, or if keys
+// or generated values include some for which equality is not
+// reflexive.
+func AssignValues[K, V comparable](keys []K, gen func(K, int) V) (*Bimap[K, V], bool) {
+	bi := New[K, V]()
+	forward, inverse := bi.newBackings(len(keys))
+	for i, k := range keys {
+		if err := bi.insertUnique(forward, inverse, k, gen(k, i)); err != nil {
+			return nil, false
+		}
+	}
+	bi.setBackings(forward, inverse)
+	return bi, true
+}
+
 // Store creates a key-value pair and returns whether or not the
 // operation was successful. Pre-existing key-value pairs (if any)
 // that involve the given key and/or the given value are silently
 // removed from the Bimap. Keys and values for which equality is
-// not reflexive are disallowed.
+// not reflexive are disallowed, as are the keys and values that the
+// Bimap's validators (see NewValidated) reject. Store also fails if it would
+// grow the Bimap beyond its maximum size (see SetMaxSize).
+// Store panics if bi is nil.
 func (bi *Bimap[K, V]) Store(key K, value V) bool {
-	if !isEqualityReflexive(key) || !isEqualityReflexive(value) {
+	bi.mustBeNonNil("Store")
+	if !bi.accepts(key, value) {
+		return false // rejected pairs must not cause bi's initialisation
+	}
+	v, keyExists := bi.fwd().Load(key)
+	k, valueExists := bi.inv().Load(value)
+	if !keyExists && !valueExists && bi.isFull() {
 		return false
 	}
-	k, exists := bi.inverse[value]
-	if exists { // value is already associated with k
-		delete(bi.forward, k)
+	bi.last = lastStored[K, V]{Pair[K, V]{key, value}, true}
+	if bi.journaling {
+		bi.journal = append(bi.journal, Pair[K, V]{key, value})
+	}
+	if keyExists && v == value {
+		return true // the pair is already present; nothing to do
 	}
-	v, exists := bi.forward[key]
-	if exists { // key is already associated with v
-		delete(bi.inverse, v)
+	if valueExists { // value is already associated with k
+		bi.fwd().Delete(k)
+	}
+	if keyExists { // key is already associated with v
+		bi.inv().Delete(v)
 	}
 	if bi.forward == nil { // bi hasn't been initialised yet
-		bi.forward = make(map[K]V)
-		bi.inverse = make(map[V]K)
+		bi.forward, bi.inverse = bi.newBackings(0)
+	}
+	bi.forward.Store(key, value)
+	bi.inverse.Store(value, key)
+	bi.peak = max(bi.peak, bi.forward.Len())
+	bi.maybeShrink() // storing may have evicted two pairs
+	if len(bi.hooks) > 0 {
+		var evicted []Pair[K, V]
+		if keyExists {
+			evicted = append(evicted, Pair[K, V]{key, v})
+		}
+		if valueExists {
+			evicted = append(evicted, Pair[K, V]{k, value})
+		}
+		bi.notify(ChangeEvent[K, V]{Stored, Pair[K, V]{key, value}, evicted})
 	}
-	bi.forward[key] = value
-	bi.inverse[value] = key
 	return true
 }
 
+// StoreDelta behaves like Store, but also returns the resulting
+// change in the Bimap's size: +1 if the pair is new and evicts no
+// pair, 0 if it evicts exactly one pair or is already present, and
+// -1 if it evicts two pairs, i.e. if the key and the value were
+// associated with other values and keys, respectively. The delta
+// is 0 if the store fails. StoreDelta panics if bi is nil.
+func (bi *Bimap[K, V]) StoreDelta(key K, value V) (delta int, ok bool) {
+	bi.mustBeNonNil("StoreDelta")
+	before := bi.Size()
+	ok = bi.Store(key, value)
+	return bi.Size() - before, ok
+}
+
+// SetMaxSize limits the number of key-value pairs in the Bimap to
+// n: subsequent calls to Store fail if they would grow the Bimap
+// beyond n pairs, but calls to Store that merely replace existing
+// pairs are still allowed. If the Bimap already contains more than
+// n pairs, none of them is removed. A zero or negative n means no
+// limit, which is the default. SetMaxSize panics if bi is nil.
+func (bi *Bimap[K, V]) SetMaxSize(n int) {
+	bi.mustBeNonNil("SetMaxSize")
+	bi.maxSize = max(n, 0)
+}
+
+// SetAutoShrink enables or disables the automatic shrinking of the
+// Bimap. When enabled, whenever deletions bring the Bimap's size
+// below ratio times its peak size since it last shrank, the Bimap
+// rebuilds its backings so as to release memory. Each rebuild takes
+// time linear in the Bimap's size; because the Bimap must first
+// lose a fraction (1 - ratio) of its pairs, the cost of rebuilds,
+// amortized over deletions, is constant for any fixed ratio.
+// A ratio outside (0, 1) disables automatic shrinking, which is
+// the default. SetAutoShrink panics if bi is nil.
+func (bi *Bimap[K, V]) SetAutoShrink(ratio float64) {
+	bi.mustBeNonNil("SetAutoShrink")
+	if !(0 < ratio && ratio < 1) {
+		ratio = 0
+	}
+	bi.shrink = ratio
+	bi.maybeShrink()
+}
+
+// maybeShrink rebuilds the Bimap's backings if automatic shrinking
+// is enabled and warranted.
+func (bi *Bimap[K, V]) maybeShrink() {
+	size := bi.Size()
+	if bi.shrink == 0 || float64(size) >= bi.shrink*float64(bi.peak) {
+		return
+	}
+	bi.reallocate()
+}
+
+// reallocate moves the Bimap's pairs to new backings of the right
+// size.
+func (bi *Bimap[K, V]) reallocate() {
+	size := bi.Size()
+	forward, inverse := bi.newBackings(size)
+	for k, v := range bi.fwd().Range {
+		forward.Store(k, v)
+		inverse.Store(v, k)
+	}
+	bi.setBackings(forward, inverse)
+}
+
+// setBackings makes forward and inverse the Bimap's backings and
+// resets its peak size to their current size.
+func (bi *Bimap[K, V]) setBackings(forward Backing[K, V], inverse Backing[V, K]) {
+	bi.forward, bi.inverse = forward, inverse
+	bi.peak = forward.Len()
+}
+
+// isFull reports whether the Bimap has reached its maximum size.
+func (bi *Bimap[K, V]) isFull() bool {
+	return bi.maxSize > 0 && bi.Size() >= bi.maxSize
+}
+
+// LastStored returns the key-value pair most recently stored by a
+// successful call to Store, even if that pair has since been
+// removed from the Bimap. The ok result is false if no pair has
+// ever been stored successfully.
+func (bi *Bimap[K, V]) LastStored() (Pair[K, V], bool) {
+	if bi == nil {
+		return Pair[K, V]{}, false
+	}
+	return bi.last.pair, bi.last.ok
+}
+
+// CanStoreAll reports whether all the given pairs could be
+// stored in the Bimap without evicting any pair, whether already
+// present in the Bimap or stored earlier in the batch. If not, it
+// also returns the index of the first problematic pair; otherwise,
+// firstConflict is -1. Pairs that Store would reject are deemed
+// problematic. CanStoreAll does not modify the Bimap.
+func (bi *Bimap[K, V]) CanStoreAll(pairs []Pair[K, V]) (ok bool, firstConflict int) {
+	if bi == nil {
+		bi = new(Bimap[K, V]) // a nil *Bimap behaves like an empty one
+	}
+	if i := bi.firstConflict(pairs, nil); i >= 0 {
+		return false, i
+	}
+	return true, -1
+}
+
+// ApplyDiff deletes the key-value pairs involving removeKeys and
+// then stores the pairs in add, and returns whether or not the
+// operation was successful. Keys in removeKeys that are absent
+// from the Bimap are ignored. The operation fails, and leaves the
+// Bimap unchanged, if storing the pairs in add after the removals
+// would evict any pair (see CanStoreAll) or if Store would reject
+// any of them. ApplyDiff panics if bi is nil.
+func (bi *Bimap[K, V]) ApplyDiff(add []Pair[K, V], removeKeys []K) (ok bool) {
+	bi.mustBeNonNil("ApplyDiff")
+	removed := make(map[K]struct{}, len(removeKeys))
+	for _, k := range removeKeys {
+		removed[k] = struct{}{}
+	}
+	if bi.firstConflict(add, removed) >= 0 {
+		return false
+	}
+	for _, k := range removeKeys {
+		bi.DeleteByKey(k)
+	}
+	for _, p := range add {
+		bi.Store(p.Key, p.Value)
+	}
+	return true
+}
+
+// MergeWithCombine stores the key-value pairs of other in the
+// Bimap, and returns whether or not the operation was successful.
+// For each key present in both bimaps, the Bimap stores the result
+// of combine applied to the key's value in the Bimap and to its
+// value in other, rather than the latter. The operation fails, and
+// leaves the Bimap unchanged, if the resulting pairs would evict any
+// pair (e.g. if combine produces a value that another key retains)
+// or if Store would reject any of them. MergeWithCombine panics if
+// bi is nil.
+func (bi *Bimap[K, V]) MergeWithCombine(other *Bimap[K, V], combine func(a, b V) V) bool {
+	bi.mustBeNonNil("MergeWithCombine")
+	add := make([]Pair[K, V], 0, other.Size())
+	var common []K
+	for k, v := range other.fwd().Range {
+		if old, exists := bi.fwd().Load(k); exists {
+			v = combine(old, v)
+			common = append(common, k)
+		}
+		add = append(add, Pair[K, V]{k, v})
+	}
+	return bi.ApplyDiff(add, common)
+}
+
+// firstConflict returns the index of the first pair that couldn't
+// be stored in the Bimap, deprived of the pairs involving the keys
+// in removed, without evicting any pair; if there is no such pair,
+// it returns -1.
+func (bi *Bimap[K, V]) firstConflict(pairs []Pair[K, V], removed map[K]struct{}) int {
+	keys := make(map[K]V, len(pairs))
+	values := make(map[V]K, len(pairs))
+	size := bi.Size()
+	for k := range removed {
+		if _, exists := bi.fwd().Load(k); exists {
+			size--
+		}
+	}
+	for i, p := range pairs {
+		if !bi.accepts(p.Key, p.Value) {
+			return i
+		}
+		if v, exists := bi.fwd().Load(p.Key); exists && v != p.Value && !contains(removed, p.Key) {
+			return i
+		}
+		if k, exists := bi.inv().Load(p.Value); exists && k != p.Key && !contains(removed, k) {
+			return i
+		}
+		if v, exists := keys[p.Key]; exists && v != p.Value {
+			return i
+		}
+		if k, exists := values[p.Value]; exists && k != p.Key {
+			return i
+		}
+		_, inBatch := keys[p.Key]
+		if !inBatch && (!bi.ContainsPair(p.Key, p.Value) || contains(removed, p.Key)) {
+			if size++; bi.maxSize > 0 && size > bi.maxSize {
+				return i
+			}
+		}
+		keys[p.Key] = p.Value
+		values[p.Value] = p.Key
+	}
+	return -1
+}
+
+// mustBeNonNil panics with an explicit message if bi is nil;
+// method is the name of the calling method.
+func (bi *Bimap[K, V]) mustBeNonNil(method string) {
+	if bi == nil {
+		panic("bimap: " + method + " called on nil *Bimap")
+	}
+}
+
+func contains[T comparable](set map[T]struct{}, t T) bool {
+	_, ok := set[t]
+	return ok
+}
+
+// insertUnique stores the pair formed by k and v in forward and
+// inverse, which are meant to become the Bimap's backings, unless
+// the Bimap disallows that pair, k or v is already present, or
+// forward has reached the Bimap's maximum size.
+func (bi *Bimap[K, V]) insertUnique(forward Backing[K, V], inverse Backing[V, K], k K, v V) error {
+	return bi.insertUniqueWithin(forward, inverse, k, v, bi.maxSize)
+}
+
+// insertUniqueWithin is like insertUnique, but it enforces limit (0
+// meaning no limit) rather than the Bimap's maximum size.
+func (bi *Bimap[K, V]) insertUniqueWithin(forward Backing[K, V], inverse Backing[V, K], k K, v V, limit int) error {
+	if !bi.accepts(k, v) {
+		return fmt.Errorf("bimap: disallowed pair %v:%v", k, v)
+	}
+	if _, exists := forward.Load(k); exists {
+		return fmt.Errorf("bimap: duplicate key %v", k)
+	}
+	if _, exists := inverse.Load(v); exists {
+		return fmt.Errorf("bimap: duplicate value %v", v)
+	}
+	if limit > 0 && forward.Len() >= limit {
+		return fmt.Errorf("bimap: more than %d pairs", limit)
+	}
+	forward.Store(k, v)
+	inverse.Store(v, k)
+	return nil
+}
+
+// accepts reports whether the Bimap allows the given key-value
+// pair to be stored.
+func (bi *Bimap[K, V]) accepts(key K, value V) bool {
+	if !isEqualityReflexive(key) || !isEqualityReflexive(value) {
+		return false
+	}
+	if bi.keyOK != nil && !bi.keyOK(key) {
+		return false
+	}
+	if bi.valOK != nil && !bi.valOK(value) {
+		return false
+	}
+	return true
+}
+
+func isNonZero[T comparable](t T) bool {
+	var zero T
+	return t != zero
+}
+
 func isEqualityReflexive[T comparable](t T) bool {
 	return t == t
 }
@@ -60,62 +488,697 @@ func isEqualityReflexive[T comparable](t T) bool {
 // or the zero value of the K type if no value is present.
 // The ok result indicates whether the key was found in the map.
 func (bi *Bimap[K, V]) LoadValue(k K) (V, bool) {
-	v, ok := bi.forward[k]
+	v, ok := bi.fwd().Load(k)
 	return v, ok
 }
 
+// LoadValueOrElse returns the value stored in the Bimap for a key,
+// or the result of calling compute if no value is present.
+// The result of compute is not stored in the Bimap, and compute is
+// only called if the key is absent.
+func (bi *Bimap[K, V]) LoadValueOrElse(k K, compute func() V) V {
+	if v, ok := bi.fwd().Load(k); ok {
+		return v
+	}
+	return compute()
+}
+
+// LoadValuesInto stores in dst[i] the value stored in the Bimap
+// for keys[i], or the zero value of the V type if no value is
+// present, for each index i. It returns the number of keys found
+// and, in order, the keys that are absent from the Bimap.
+// LoadValuesInto panics if dst is shorter than keys.
+func (bi *Bimap[K, V]) LoadValuesInto(dst []V, keys []K) (n int, missing []K) {
+	if len(dst) < len(keys) {
+		const tmpl = "bimap: dst too short (%d) for keys (%d)"
+		panic(fmt.Sprintf(tmpl, len(dst), len(keys)))
+	}
+	for i, k := range keys {
+		v, ok := bi.fwd().Load(k)
+		if !ok {
+			missing = append(missing, k)
+		} else {
+			n++
+		}
+		dst[i] = v
+	}
+	return n, missing
+}
+
+// ValueEquals reports whether k is present in the Bimap and is
+// associated with the expected value.
+func (bi *Bimap[K, V]) ValueEquals(k K, expected V) bool {
+	v, ok := bi.fwd().Load(k)
+	return ok && v == expected
+}
+
+// ContainsPair reports whether the Bimap contains the key-value
+// pair formed by k and v.
+func (bi *Bimap[K, V]) ContainsPair(k K, v V) bool {
+	return bi.ValueEquals(k, v)
+}
+
 // LoadKey returns the key stored in the Bimap for a key,
 // or the zero value of the V type if no key is present.
 // The ok result indicates whether the value was found in the
 // map.
 func (bi *Bimap[K, V]) LoadKey(v V) (K, bool) {
-	k, ok := bi.inverse[v]
+	k, ok := bi.inv().Load(v)
 	return k, ok
 }
 
+// ValueLookup returns a function that behaves like bi.LoadValue.
+// The function observes subsequent changes to the Bimap.
+func (bi *Bimap[K, V]) ValueLookup() func(K) (V, bool) {
+	return bi.LoadValue
+}
+
+// KeyLookup returns a function that behaves like bi.LoadKey.
+// The function observes subsequent changes to the Bimap.
+func (bi *Bimap[K, V]) KeyLookup() func(V) (K, bool) {
+	return bi.LoadKey
+}
+
 // DeleteByKey deletes the key-value pair involving the given
 // key.
 func (bi *Bimap[K, V]) DeleteByKey(k K) {
-	v := bi.forward[k]
-	delete(bi.forward, k)
-	delete(bi.inverse, v)
+	v, exists := bi.fwd().Load(k)
+	if !exists {
+		return // otherwise, we may delete an unrelated zero value
+	}
+	bi.fwd().Delete(k)
+	bi.inv().Delete(v)
+	bi.maybeShrink()
+	bi.notify(ChangeEvent[K, V]{Kind: Deleted, Pair: Pair[K, V]{k, v}})
 }
 
 // DeleteByValue deletes the key-value pair involving the given
 // value.
 func (bi *Bimap[K, V]) DeleteByValue(v V) {
-	k := bi.inverse[v]
-	delete(bi.inverse, v)
-	delete(bi.forward, k)
+	k, exists := bi.inv().Load(v)
+	if !exists {
+		return // otherwise, we may delete an unrelated zero key
+	}
+	bi.inv().Delete(v)
+	bi.fwd().Delete(k)
+	bi.maybeShrink()
+	bi.notify(ChangeEvent[K, V]{Kind: Deleted, Pair: Pair[K, V]{k, v}})
+}
+
+// CountKeysIn returns the number of key-value pairs in the Bimap
+// whose key is among the given keys. Duplicate keys are counted
+// only once.
+func (bi *Bimap[K, V]) CountKeysIn(keys ...K) int {
+	seen := make(map[K]struct{}, len(keys))
+	for _, k := range keys {
+		if _, exists := bi.fwd().Load(k); exists {
+			seen[k] = struct{}{}
+		}
+	}
+	return len(seen)
+}
+
+// CountValuesIn returns the number of key-value pairs in the Bimap
+// whose value is among the given values. Duplicate values are
+// counted only once.
+func (bi *Bimap[K, V]) CountValuesIn(values ...V) int {
+	seen := make(map[V]struct{}, len(values))
+	for _, v := range values {
+		if _, exists := bi.inv().Load(v); exists {
+			seen[v] = struct{}{}
+		}
+	}
+	return len(seen)
+}
+
+// AtLeast reports whether pred returns true for at least n of the
+// key-value pairs in the Bimap. It stops calling pred as soon as
+// it has found n such pairs.
+func (bi *Bimap[K, V]) AtLeast(n int, pred func(K, V) bool) bool {
+	if n <= 0 {
+		return true
+	}
+	for k, v := range bi.fwd().Range {
+		if pred(k, v) {
+			n--
+			if n == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PairsWhere returns a slice of the key-value pairs in the Bimap
+// for which rel returns true.
+func (bi *Bimap[K, V]) PairsWhere(rel func(K, V) bool) []Pair[K, V] {
+	var pairs []Pair[K, V]
+	for k, v := range bi.fwd().Range {
+		if rel(k, v) {
+			pairs = append(pairs, Pair[K, V]{k, v})
+		}
+	}
+	return pairs
+}
+
+// IndexedRange calls f sequentially for each key-value pair in the
+// Bimap, in unspecified order, along with the pair's position in
+// that order, starting from 0. If f returns false, IndexedRange
+// stops the iteration.
+func (bi *Bimap[K, V]) IndexedRange(f func(i int, k K, v V) bool) {
+	var i int
+	for k, v := range bi.fwd().Range {
+		if !f(i, k, v) {
+			return
+		}
+		i++
+	}
+}
+
+// Retain removes from the Bimap every key-value pair for which
+// pred returns false, and returns the number of pairs removed.
+func (bi *Bimap[K, V]) Retain(pred func(K, V) bool) int {
+	if bi == nil {
+		return 0
+	}
+	var removed int
+	for k, v := range bi.fwd().Range {
+		if !pred(k, v) {
+			bi.fwd().Delete(k)
+			bi.inv().Delete(v)
+			removed++
+			bi.notify(ChangeEvent[K, V]{Kind: Deleted, Pair: Pair[K, V]{k, v}})
+		}
+	}
+	bi.maybeShrink()
+	return removed
+}
+
+// RotateValues cyclically shifts the values among the given keys:
+// the first key gets associated with the second key's value, the
+// second key with the third key's value, and so on, and the last
+// key with the first key's value. It returns false, and leaves the
+// Bimap unchanged, if any of the keys is absent from the Bimap or
+// if keys contains duplicates.
+func (bi *Bimap[K, V]) RotateValues(keys ...K) bool {
+	values := make([]V, len(keys))
+	seen := make(map[K]struct{}, len(keys))
+	for i, k := range keys {
+		v, exists := bi.fwd().Load(k)
+		if !exists || contains(seen, k) {
+			return false
+		}
+		seen[k] = struct{}{}
+		values[i] = v
+	}
+	for i, k := range keys {
+		v := values[(i+1)%len(values)]
+		bi.forward.Store(k, v)
+		bi.inverse.Store(v, k)
+	}
+	if len(keys) > 1 && len(bi.hooks) > 0 {
+		for i, k := range keys {
+			bi.notify(ChangeEvent[K, V]{Kind: Deleted, Pair: Pair[K, V]{k, values[i]}})
+		}
+		for i, k := range keys {
+			v := values[(i+1)%len(values)]
+			bi.notify(ChangeEvent[K, V]{Kind: Stored, Pair: Pair[K, V]{k, v}})
+		}
+	}
+	return true
+}
+
+// Drain removes all key-value pairs from the Bimap and returns
+// them.
+func (bi *Bimap[K, V]) Drain() []Pair[K, V] {
+	if bi == nil {
+		return []Pair[K, V]{}
+	}
+	pairs := make([]Pair[K, V], 0, bi.Size())
+	for k, v := range bi.fwd().Range {
+		pairs = append(pairs, Pair[K, V]{k, v})
+	}
+	bi.forward, bi.inverse = nil, nil
+	bi.peak = 0
+	for _, p := range pairs {
+		bi.notify(ChangeEvent[K, V]{Kind: Deleted, Pair: p})
+	}
+	return pairs
+}
+
+// TransformValues replaces each value in the Bimap by the result
+// of applying f to it, and returns whether or not the operation
+// was successful. The operation fails, and leaves the Bimap
+// unchanged, if f produces duplicate values or values that Store
+// would reject. TransformValues panics if bi is nil.
+func (bi *Bimap[K, V]) TransformValues(f func(V) V) bool {
+	bi.mustBeNonNil("TransformValues")
+	return bi.rebuild(func(k K, v V) (K, V) { return k, f(v) })
+}
+
+// TransformKeys replaces each key in the Bimap by the result of
+// applying f to it, and returns whether or not the operation was
+// successful. The operation fails, and leaves the Bimap unchanged,
+// if f produces duplicate keys or keys that Store would reject.
+// TransformKeys panics if bi is nil.
+func (bi *Bimap[K, V]) TransformKeys(f func(K) K) bool {
+	bi.mustBeNonNil("TransformKeys")
+	return bi.rebuild(func(k K, v V) (K, V) { return f(k), v })
+}
+
+// rebuild replaces the Bimap's pairs by the results of applying f
+// to them, unless those results contain duplicate keys, duplicate
+// values, or pairs that Store would reject; it reports whether it
+// did. Because rebuild never grows the Bimap, it allows the Bimap
+// to remain above its maximum size (see SetMaxSize).
+func (bi *Bimap[K, V]) rebuild(f func(K, V) (K, V)) bool {
+	forward, inverse := bi.newBackings(bi.Size())
+	for k, v := range bi.fwd().Range {
+		k, v = f(k, v)
+		if err := bi.insertUniqueWithin(forward, inverse, k, v, 0); err != nil {
+			return false
+		}
+	}
+	old := bi.fwd()
+	bi.setBackings(forward, inverse)
+	bi.notifyReplaced(old)
+	return true
+}
+
+// Repair restores the internal consistency of the Bimap by
+// removing every entry of the forward map whose value does not
+// map back to its key, and every entry of the inverse map whose
+// key does not map back to its value. It returns the number of
+// entries removed. Repair is a best-effort recovery tool; a
+// Bimap that is only ever manipulated through its methods never
+// needs repairing.
+func (bi *Bimap[K, V]) Repair() (removed int) {
+	for k, v := range bi.fwd().Range {
+		if k2, ok := bi.inv().Load(v); !ok || k2 != k {
+			bi.fwd().Delete(k)
+			removed++
+		}
+	}
+	for v, k := range bi.inv().Range {
+		if v2, ok := bi.fwd().Load(k); !ok || v2 != v {
+			bi.inv().Delete(v)
+			removed++
+		}
+	}
+	return removed
 }
 
 // Size returns the number of key-value pairs in the Bimap.
 // The complexity is O(1).
 func (bi *Bimap[K, V]) Size() int {
-	return len(bi.forward)
+	return bi.fwd().Len()
+}
+
+// SameSize reports whether the Bimap and other contain the same
+// number of key-value pairs. A nil *Bimap is deemed empty.
+func (bi *Bimap[K, V]) SameSize(other *Bimap[K, V]) bool {
+	return sizeOf(bi) == sizeOf(other)
+}
+
+func sizeOf[K, V comparable](bi *Bimap[K, V]) int {
+	if bi == nil {
+		return 0
+	}
+	return bi.Size()
+}
+
+// Only returns the sole key-value pair of the Bimap. The ok result
+// is false if the Bimap is empty or contains more than one pair.
+func (bi *Bimap[K, V]) Only() (Pair[K, V], bool) {
+	if bi.Size() != 1 {
+		return Pair[K, V]{}, false
+	}
+	for k, v := range bi.fwd().Range {
+		return Pair[K, V]{k, v}, true
+	}
+	return Pair[K, V]{}, false // Len and Range of a third-party Backing disagree
+}
+
+// Equal reports whether the Bimap and other contain the same
+// key-value pairs. A nil *Bimap is deemed empty. Because of this
+// method, github.com/google/go-cmp compares bimaps by their
+// contents without the need for a custom cmp.Option.
+func (bi *Bimap[K, V]) Equal(other *Bimap[K, V]) bool {
+	if sizeOf(bi) != sizeOf(other) {
+		return false
+	}
+	for k, v := range bi.fwd().Range {
+		if v2, ok := other.fwd().Load(k); !ok || v2 != v {
+			return false
+		}
+	}
+	return true
+}
+
+// IsDisjoint reports whether the Bimap and other have no key and
+// no value in common.
+func (bi *Bimap[K, V]) IsDisjoint(other *Bimap[K, V]) bool {
+	if bi.Size() > other.Size() {
+		bi, other = other, bi
+	}
+	for k, v := range bi.fwd().Range {
+		if _, exists := other.fwd().Load(k); exists {
+			return false
+		}
+		if _, exists := other.inv().Load(v); exists {
+			return false
+		}
+	}
+	return true
+}
+
+// SymmetricDifference returns a new Bimap containing the key-value
+// pairs present in either the Bimap or other but not in both.
+// Because a key (or value) may be associated with different values
+// (or keys) in the two bimaps, the pairs that are only present in
+// the Bimap take precedence: any pair that is only present in
+// other and that shares its key or its value with such a pair is
+// left out of the result.
+func (bi *Bimap[K, V]) SymmetricDifference(other *Bimap[K, V]) *Bimap[K, V] {
+	res := New[K, V]()
+	for k, v := range other.fwd().Range {
+		if !bi.ContainsPair(k, v) {
+			res.Store(k, v)
+		}
+	}
+	for k, v := range bi.fwd().Range {
+		if !other.ContainsPair(k, v) {
+			res.Store(k, v)
+		}
+	}
+	return res
+}
+
+// SelectKeys returns a new Bimap containing the key-value pairs
+// of the Bimap whose key is among the given keys. Keys absent from
+// the Bimap are ignored.
+func (bi *Bimap[K, V]) SelectKeys(keys ...K) *Bimap[K, V] {
+	res := New[K, V]()
+	for _, k := range keys {
+		if v, exists := bi.fwd().Load(k); exists {
+			res.Store(k, v)
+		}
+	}
+	return res
+}
+
+// SelectValues returns a new Bimap containing the key-value pairs
+// of the Bimap whose value is among the given values. Values absent
+// from the Bimap are ignored.
+func (bi *Bimap[K, V]) SelectValues(values ...V) *Bimap[K, V] {
+	res := New[K, V]()
+	for _, v := range values {
+		if k, exists := bi.inv().Load(v); exists {
+			res.Store(k, v)
+		}
+	}
+	return res
+}
+
+// DistanceTo returns the number of key-value pairs present in
+// either the Bimap or other but not in both. Unlike the size of
+// the result of SymmetricDifference, that number accounts for all
+// such pairs, even those that conflict with one another.
+func (bi *Bimap[K, V]) DistanceTo(other *Bimap[K, V]) int {
+	var shared int
+	for k, v := range bi.fwd().Range {
+		if other.ContainsPair(k, v) {
+			shared++
+		}
+	}
+	return bi.Size() + other.Size() - 2*shared
+}
+
+// An Overlap summarizes how much two bimaps have in common.
+type Overlap struct {
+	SharedKeys   int // number of keys present in both bimaps
+	SharedValues int // number of values present in both bimaps
+	ExactMatches int // number of key-value pairs present in both bimaps
+}
+
+// OverlapStats compares the Bimap to other in a single pass over
+// the smaller of the two.
+func (bi *Bimap[K, V]) OverlapStats(other *Bimap[K, V]) Overlap {
+	if bi.Size() > other.Size() {
+		bi, other = other, bi
+	}
+	var o Overlap
+	for k, v := range bi.fwd().Range {
+		if v2, exists := other.fwd().Load(k); exists {
+			o.SharedKeys++
+			if v2 == v {
+				o.ExactMatches++
+			}
+		}
+		if _, exists := other.inv().Load(v); exists {
+			o.SharedValues++
+		}
+	}
+	return o
+}
+
+// Diff compares the Bimap to other, key by key. It returns the
+// pairs of other whose key is absent from the Bimap (added), the
+// pairs of the Bimap whose key is absent from other (removed), and
+// the pairs of other whose key is associated with a different
+// value in the Bimap (changed).
+func (bi *Bimap[K, V]) Diff(other *Bimap[K, V]) (added, removed, changed []Pair[K, V]) {
+	for k, v := range other.fwd().Range {
+		switch v2, exists := bi.fwd().Load(k); {
+		case !exists:
+			added = append(added, Pair[K, V]{k, v})
+		case v2 != v:
+			changed = append(changed, Pair[K, V]{k, v})
+		}
+	}
+	for k, v := range bi.fwd().Range {
+		if _, exists := other.fwd().Load(k); !exists {
+			removed = append(removed, Pair[K, V]{k, v})
+		}
+	}
+	return added, removed, changed
 }
 
 // Keys returns a slice of the keys in the Bimap.
 func (bi *Bimap[K, V]) Keys() []K {
-	var keys []K
-	for k := range bi.forward {
-		keys = append(keys, k)
+	return bi.AppendKeys(nil)
+}
+
+// AppendKeys appends the keys in the Bimap to dst and returns the
+// extended slice.
+func (bi *Bimap[K, V]) AppendKeys(dst []K) []K {
+	for k := range bi.fwd().Range {
+		dst = append(dst, k)
 	}
-	return keys
+	return dst
 }
 
 // Values returns a slice of the values in the Bimap.
 func (bi *Bimap[K, V]) Values() []V {
-	var values []V
-	for v := range bi.inverse {
-		values = append(values, v)
+	return bi.AppendValues(nil)
+}
+
+// AppendValues appends the values in the Bimap to dst and returns
+// the extended slice.
+func (bi *Bimap[K, V]) AppendValues(dst []V) []V {
+	for v := range bi.inv().Range {
+		dst = append(dst, v)
 	}
-	return values
+	return dst
+}
+
+// KeySet returns the set of keys in the Bimap.
+func (bi *Bimap[K, V]) KeySet() map[K]struct{} {
+	set := make(map[K]struct{}, bi.fwd().Len())
+	for k := range bi.fwd().Range {
+		set[k] = struct{}{}
+	}
+	return set
+}
+
+// ValueSet returns the set of values in the Bimap.
+func (bi *Bimap[K, V]) ValueSet() map[V]struct{} {
+	set := make(map[V]struct{}, bi.inv().Len())
+	for v := range bi.inv().Range {
+		set[v] = struct{}{}
+	}
+	return set
+}
+
+// InversePairs returns a slice of the key-value pairs in the
+// Bimap, each with its value first and its key second.
+func (bi *Bimap[K, V]) InversePairs() []Pair[V, K] {
+	pairs := make([]Pair[V, K], 0, bi.inv().Len())
+	for v, k := range bi.inv().Range {
+		pairs = append(pairs, Pair[V, K]{Key: v, Value: k})
+	}
+	return pairs
 }
 
 // String returns a string representing the Bimap. That string
 // representation is similar to the string representation of a
 // built-in map.
 func (bi *Bimap[K, V]) String() string {
-	return fmt.Sprintf("Bi%v", bi.forward)
+	m, ok := bi.fwd().(builtinMap[K, V])
+	if !ok {
+		m = make(builtinMap[K, V], bi.Size())
+		for k, v := range bi.fwd().Range {
+			m[k] = v
+		}
+	}
+	return fmt.Sprintf("Bi%v", map[K]V(m))
+}
+
+// GroupBy returns the key-value pairs of bi grouped by the result
+// of applying bucket to them. The order of pairs within each group
+// is unspecified.
+func GroupBy[K, V, G comparable](bi *Bimap[K, V], bucket func(K, V) G) map[G][]Pair[K, V] {
+	groups := make(map[G][]Pair[K, V])
+	for k, v := range bi.fwd().Range {
+		g := bucket(k, v)
+		groups[g] = append(groups[g], Pair[K, V]{k, v})
+	}
+	return groups
+}
+
+// IndexBy returns the key-value pairs of bi indexed by the result
+// of applying index to them. The ok result is false, and the map is
+// nil, if index produces the same result for two different pairs.
+func IndexBy[K, V, I comparable](bi *Bimap[K, V], index func(K, V) I) (map[I]Pair[K, V], bool) {
+	m := make(map[I]Pair[K, V], bi.Size())
+	for k, v := range bi.fwd().Range {
+		i := index(k, v)
+		if _, exists := m[i]; exists {
+			return nil, false
+		}
+		m[i] = Pair[K, V]{k, v}
+	}
+	return m, true
+}
+
+// An Occurrence identifies a key of a labelled Bimap.
+type Occurrence[K comparable] struct {
+	ID  string // the Bimap's label
+	Key K
+}
+
+// CombinedInverse returns an index of the values of bimaps, which
+// are labelled by their map key, to the keys associated with them.
+// Because a value occurs at most once in each Bimap, each value is
+// mapped to at most one Occurrence per Bimap; occurrences are sorted
+// by ID.
+func CombinedInverse[K, V comparable](bimaps map[string]*Bimap[K, V]) map[V][]Occurrence[K] {
+	index := make(map[V][]Occurrence[K])
+	for id, bi := range bimaps {
+		for v, k := range bi.inv().Range {
+			index[v] = append(index[v], Occurrence[K]{id, k})
+		}
+	}
+	for _, occs := range index {
+		slices.SortFunc(occs, func(a, b Occurrence[K]) bool {
+			return a.ID < b.ID
+		})
+	}
+	return index
+}
+
+// Compose returns the composition of f and g, i.e. a new Bimap
+// that associates each key a of f with g's value for f's value for
+// a. Keys of f whose value is not a key of g are left out of the
+// result. Because both f and g form one-to-one correspondences, so
+// does their composition; the ok result is false only if f or g
+// is internally inconsistent (see Repair).
+func Compose[A, B, C comparable](f *Bimap[A, B], g *Bimap[B, C]) (*Bimap[A, C], bool) {
+	res := New[A, C]()
+	forward, inverse := res.newBackings(0)
+	for a, b := range f.fwd().Range {
+		c, ok := g.LoadValue(b)
+		if !ok {
+			continue
+		}
+		if err := res.insertUnique(forward, inverse, a, c); err != nil {
+			return nil, false
+		}
+	}
+	res.setBackings(forward, inverse)
+	return res, true
+}
+
+// CloneMap returns a new Bimap that associates each key of bi with
+// the result of applying f to the key's value; bi is left
+// unchanged. The ok result is false if f produces duplicate values
+// or values for which equality is not reflexive.
+func CloneMap[K, V, V2 comparable](bi *Bimap[K, V], f func(V) V2) (*Bimap[K, V2], bool) {
+	res := New[K, V2]()
+	forward, inverse := res.newBackings(bi.Size())
+	for k, v := range bi.fwd().Range {
+		if err := res.insertUnique(forward, inverse, k, f(v)); err != nil {
+			return nil, false
+		}
+	}
+	res.setBackings(forward, inverse)
+	return res, true
+}
+
+// LookupChain2 resolves a to a value b through first, and then b to
+// a value c through second. The ok result is false if a is absent
+// from first or b is absent from second.
+func LookupChain2[A, B, C comparable](first *Bimap[A, B], second *Bimap[B, C], a A) (C, bool) {
+	b, ok := first.LoadValue(a)
+	if !ok {
+		var zero C
+		return zero, false
+	}
+	return second.LoadValue(b)
+}
+
+// Batches returns an iterator over the key-value pairs of bi in
+// batches of size pairs; the last batch may contain fewer pairs.
+// The order of pairs is unspecified. Batches panics if size is
+// less than 1.
+func Batches[K, V comparable](bi *Bimap[K, V], size int) iter.Seq[[]Pair[K, V]] {
+	if size < 1 {
+		panic("bimap: batch size must be positive")
+	}
+	return func(yield func([]Pair[K, V]) bool) {
+		var batch []Pair[K, V]
+		for k, v := range bi.fwd().Range {
+			if batch == nil {
+				batch = make([]Pair[K, V], 0, min(size, bi.Size()))
+			}
+			batch = append(batch, Pair[K, V]{k, v})
+			if len(batch) == size {
+				if !yield(batch) {
+					return
+				}
+				batch = nil
+			}
+		}
+		if len(batch) > 0 {
+			yield(batch)
+		}
+	}
+}
+
+// PopulateKeyFilter calls add once for each key in bi, e.g. in order
+// to feed an external probabilistic filter. The order of calls is
+// unspecified.
+func PopulateKeyFilter[K, V comparable](bi *Bimap[K, V], add func(K)) {
+	for k := range bi.fwd().Range {
+		add(k)
+	}
+}
+
+// PopulateValueFilter calls add once for each value in bi, e.g. in
+// order to feed an external probabilistic filter. The order of calls
+// is unspecified.
+func PopulateValueFilter[K, V comparable](bi *Bimap[K, V], add func(V)) {
+	for v := range bi.inv().Range {
+		add(v)
+	}
 }
@@ -3,6 +3,8 @@ package bimap
 import (
 	"math"
 	"sort"
+	"strconv"
+	"strings"
 	"testing"
 
 	"golang.org/x/exp/slices"
@@ -136,3 +138,1765 @@ func TestString(t *testing.T) {
 		t.Errorf("got %v; want %v", got, want)
 	}
 }
+
+func TestThatRepairLeavesAConsistentBimapUntouched(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	if removed := bi.Repair(); removed != 0 {
+		t.Errorf("bi.Repair() = %d; want %d", removed, 0)
+	}
+	if size := bi.Size(); size != 2 {
+		t.Errorf("bi.Size() = %d; want %d", size, 2)
+	}
+}
+
+func TestThatRepairRemovesOrphanedEntries(t *testing.T) {
+	bi := &Bimap[int, string]{
+		forward: builtinMap[int, string]{1: "one", 2: "two", 3: "three"},
+		inverse: builtinMap[string, int]{"one": 1, "two": 3, "four": 4},
+	}
+	want := 4 // 2:"two", 3:"three", "two":3, "four":4
+	if removed := bi.Repair(); removed != want {
+		t.Errorf("bi.Repair() = %d; want %d", removed, want)
+	}
+	if size, n := bi.Size(), bi.inverse.Len(); size != 1 || n != 1 {
+		t.Errorf("got sizes %d, %d; want 1, 1", size, n)
+	}
+	if v, exists := bi.LoadValue(1); !exists || v != "one" {
+		t.Errorf("got %q, %t; want %q, %t", v, exists, "one", true)
+	}
+	if k, exists := bi.LoadKey("one"); !exists || k != 1 {
+		t.Errorf("got %d, %t; want %d, %t", k, exists, 1, true)
+	}
+}
+
+func TestAppendKeysAppendsToTheDestination(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	dst := []int{42}
+	got := bi.AppendKeys(dst)
+	sort.Ints(got[1:])
+	want := []int{42, 1, 2}
+	if ok := slices.Equal(got, want); !ok {
+		t.Errorf("got %v; want %v", got, want)
+	}
+}
+
+func TestAppendValuesAppendsToTheDestination(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	dst := []string{"zero"}
+	got := bi.AppendValues(dst)
+	sort.Strings(got[1:])
+	want := []string{"zero", "one", "two"}
+	if ok := slices.Equal(got, want); !ok {
+		t.Errorf("got %v; want %v", got, want)
+	}
+}
+
+func BenchmarkKeys(b *testing.B) {
+	bi := New[int, int]()
+	for i := 0; i < 100; i++ {
+		bi.Store(i, i)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = bi.Keys()
+	}
+}
+
+func BenchmarkAppendKeys(b *testing.B) {
+	bi := New[int, int]()
+	for i := 0; i < 100; i++ {
+		bi.Store(i, i)
+	}
+	var buf []int
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = bi.AppendKeys(buf[:0])
+	}
+}
+
+func TestCanStoreAll(t *testing.T) {
+	cases := []struct {
+		desc  string
+		pairs []Pair[int, string]
+		ok    bool
+		index int
+	}{
+		{
+			desc:  "clean batch",
+			pairs: []Pair[int, string]{{3, "three"}, {4, "four"}},
+			ok:    true,
+			index: -1,
+		}, {
+			desc:  "batch containing an existing pair",
+			pairs: []Pair[int, string]{{1, "one"}, {3, "three"}},
+			ok:    true,
+			index: -1,
+		}, {
+			desc:  "key conflicting with an existing pair",
+			pairs: []Pair[int, string]{{3, "three"}, {1, "uno"}},
+			ok:    false,
+			index: 1,
+		}, {
+			desc:  "value conflicting with an existing pair",
+			pairs: []Pair[int, string]{{3, "three"}, {4, "four"}, {5, "two"}},
+			ok:    false,
+			index: 2,
+		}, {
+			desc:  "keys conflicting within the batch",
+			pairs: []Pair[int, string]{{3, "three"}, {3, "tres"}},
+			ok:    false,
+			index: 1,
+		}, {
+			desc:  "values conflicting within the batch",
+			pairs: []Pair[int, string]{{3, "three"}, {4, "three"}},
+			ok:    false,
+			index: 1,
+		},
+	}
+	for _, c := range cases {
+		bi := New[int, string]()
+		bi.Store(1, "one")
+		bi.Store(2, "two")
+		ok, index := bi.CanStoreAll(c.pairs)
+		if ok != c.ok || index != c.index {
+			t.Errorf("%s: got %t, %d; want %t, %d", c.desc, ok, index, c.ok, c.index)
+		}
+		if size := bi.Size(); size != 2 {
+			t.Errorf("%s: bi.Size() = %d; want %d", c.desc, size, 2)
+		}
+	}
+}
+
+func TestThatNewNonZeroRejectsZeroKeysAndValues(t *testing.T) {
+	cases := []struct {
+		key   int
+		value string
+		want  bool
+	}{
+		{0, "zero", false},
+		{1, "", false},
+		{0, "", false},
+		{1, "one", true},
+	}
+	for _, c := range cases {
+		bi := NewNonZero[int, string]()
+		got := bi.Store(c.key, c.value)
+		if size := bi.Size(); got != c.want || (size == 1) != c.want {
+			t.Errorf("Store(%d, %q): got %t, %d; want %t", c.key, c.value, got, size, c.want)
+		}
+	}
+}
+
+func TestThatNewAcceptsZeroKeysAndValues(t *testing.T) {
+	bi := New[int, string]()
+	if ok := bi.Store(0, ""); !ok {
+		t.Errorf("got %t; want %t", ok, true)
+	}
+}
+
+func TestValueEquals(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(0, "")
+	cases := []struct {
+		key      int
+		expected string
+		want     bool
+	}{
+		{1, "one", true},
+		{1, "two", false},
+		{0, "", true},
+		{2, "", false},
+	}
+	for _, c := range cases {
+		if got := bi.ValueEquals(c.key, c.expected); got != c.want {
+			t.Errorf("ValueEquals(%d, %q) = %t; want %t", c.key, c.expected, got, c.want)
+		}
+	}
+}
+
+func TestInversePairsPutsValuesFirst(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	bi.Store(3, "three")
+	got := bi.InversePairs()
+	slices.SortFunc(got, func(a, b Pair[string, int]) bool {
+		return a.Key < b.Key
+	})
+	want := []Pair[string, int]{{"one", 1}, {"three", 3}, {"two", 2}}
+	if ok := slices.Equal(got, want); !ok {
+		t.Errorf("got %v; want %v", got, want)
+	}
+	if cap(got) != bi.Size() {
+		t.Errorf("got capacity %d; want %d", cap(got), bi.Size())
+	}
+}
+
+func TestEqual(t *testing.T) {
+	a := New[int, string]()
+	a.Store(1, "one")
+	a.Store(2, "two")
+	b := New[int, string]()
+	b.Store(2, "two")
+	b.Store(1, "one")
+	c := New[int, string]()
+	c.Store(1, "one")
+	c.Store(2, "deux")
+	d := New[int, string]()
+	d.Store(1, "one")
+	var nilBimap *Bimap[int, string]
+	cases := []struct {
+		desc string
+		x, y *Bimap[int, string]
+		want bool
+	}{
+		{"same pairs", a, b, true},
+		{"different values", a, c, false},
+		{"different sizes", a, d, false},
+		{"zero value and empty bimap", new(Bimap[int, string]), New[int, string](), true},
+		{"nil and empty bimap", nilBimap, New[int, string](), true},
+		{"nil and non-empty bimap", nilBimap, d, false},
+		{"nil and nil", nilBimap, nilBimap, true},
+	}
+	for _, c := range cases {
+		if got := c.x.Equal(c.y); got != c.want {
+			t.Errorf("%s: got %t; want %t", c.desc, got, c.want)
+		}
+		if got := c.y.Equal(c.x); got != c.want {
+			t.Errorf("%s (reversed): got %t; want %t", c.desc, got, c.want)
+		}
+	}
+}
+
+func TestRetainByKey(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	bi.Store(3, "three")
+	bi.Store(4, "four")
+	removed := bi.Retain(func(k int, _ string) bool { return k%2 == 0 })
+	if removed != 2 {
+		t.Errorf("got %d; want %d", removed, 2)
+	}
+	got := bi.Keys()
+	sort.Ints(got)
+	want := []int{2, 4}
+	if ok := slices.Equal(got, want); !ok {
+		t.Errorf("got %v; want %v", got, want)
+	}
+	if _, exists := bi.LoadKey("one"); exists {
+		t.Errorf("value %q unexpectedly present", "one")
+	}
+}
+
+func TestRetainByValue(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	bi.Store(3, "three")
+	removed := bi.Retain(func(_ int, v string) bool { return len(v) == 3 })
+	if removed != 1 {
+		t.Errorf("got %d; want %d", removed, 1)
+	}
+	got := bi.Values()
+	sort.Strings(got)
+	want := []string{"one", "two"}
+	if ok := slices.Equal(got, want); !ok {
+		t.Errorf("got %v; want %v", got, want)
+	}
+	if _, exists := bi.LoadValue(3); exists {
+		t.Errorf("key %d unexpectedly present", 3)
+	}
+}
+
+func TestThatStoringAnExistingPairEvictsNothing(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	if ok := bi.Store(1, "one"); !ok {
+		t.Errorf("got %t; want %t", ok, true)
+	}
+	if size := bi.Size(); size != 2 {
+		t.Errorf("bi.Size() = %d; want %d", size, 2)
+	}
+	if v, exists := bi.LoadValue(1); !exists || v != "one" {
+		t.Errorf("got %q, %t; want %q, %t", v, exists, "one", true)
+	}
+	if k, exists := bi.LoadKey("one"); !exists || k != 1 {
+		t.Errorf("got %d, %t; want %d, %t", k, exists, 1, true)
+	}
+}
+
+func TestContainsPair(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	cases := []struct {
+		key   int
+		value string
+		want  bool
+	}{
+		{1, "one", true},
+		{1, "two", false},
+		{3, "three", false},
+	}
+	for _, c := range cases {
+		if got := bi.ContainsPair(c.key, c.value); got != c.want {
+			t.Errorf("ContainsPair(%d, %q) = %t; want %t", c.key, c.value, got, c.want)
+		}
+	}
+}
+
+func TestSymmetricDifference(t *testing.T) {
+	cases := []struct {
+		desc string
+		x, y map[int]string
+		want map[int]string
+	}{
+		{
+			desc: "disjoint",
+			x:    map[int]string{1: "one", 2: "two"},
+			y:    map[int]string{3: "three"},
+			want: map[int]string{1: "one", 2: "two", 3: "three"},
+		}, {
+			desc: "overlapping",
+			x:    map[int]string{1: "one", 2: "two"},
+			y:    map[int]string{2: "two", 3: "three"},
+			want: map[int]string{1: "one", 3: "three"},
+		}, {
+			desc: "identical",
+			x:    map[int]string{1: "one", 2: "two"},
+			y:    map[int]string{1: "one", 2: "two"},
+			want: map[int]string{},
+		}, {
+			desc: "conflicting keys and values",
+			x:    map[int]string{1: "one", 2: "two"},
+			y:    map[int]string{1: "uno", 3: "two", 4: "four"},
+			want: map[int]string{1: "one", 2: "two", 4: "four"},
+		},
+	}
+	for _, c := range cases {
+		x := New[int, string]()
+		for k, v := range c.x {
+			x.Store(k, v)
+		}
+		y := New[int, string]()
+		for k, v := range c.y {
+			y.Store(k, v)
+		}
+		want := New[int, string]()
+		for k, v := range c.want {
+			want.Store(k, v)
+		}
+		if got := x.SymmetricDifference(y); !got.Equal(want) {
+			t.Errorf("%s: got %v; want %v", c.desc, got, want)
+		}
+	}
+}
+
+func TestCountKeysIn(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	bi.Store(3, "three")
+	cases := []struct {
+		keys []int
+		want int
+	}{
+		{nil, 0},
+		{[]int{1, 3, 5}, 2},
+		{[]int{4, 5}, 0},
+		{[]int{2, 2, 2, 1}, 2},
+	}
+	for _, c := range cases {
+		if got := bi.CountKeysIn(c.keys...); got != c.want {
+			t.Errorf("CountKeysIn(%v) = %d; want %d", c.keys, got, c.want)
+		}
+	}
+}
+
+func TestCountValuesIn(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	bi.Store(3, "three")
+	cases := []struct {
+		values []string
+		want   int
+	}{
+		{nil, 0},
+		{[]string{"one", "three", "five"}, 2},
+		{[]string{"four", "five"}, 0},
+		{[]string{"two", "two", "one"}, 2},
+	}
+	for _, c := range cases {
+		if got := bi.CountValuesIn(c.values...); got != c.want {
+			t.Errorf("CountValuesIn(%v) = %d; want %d", c.values, got, c.want)
+		}
+	}
+}
+
+func TestThatARejectedFirstStoreDoesNotAllocate(t *testing.T) {
+	bi := new(Bimap[float64, string])
+	allocs := testing.AllocsPerRun(100, func() {
+		bi.Store(math.NaN(), "NaN")
+	})
+	if allocs != 0 {
+		t.Errorf("got %v allocations; want 0", allocs)
+	}
+	if bi.forward != nil || bi.inverse != nil {
+		t.Error("rejected store unexpectedly initialised the bimap")
+	}
+}
+
+func TestKeySetAndValueSetMatchTheBimapContents(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	bi.Store(3, "three")
+	keys := bi.KeySet()
+	values := bi.ValueSet()
+	if len(keys) != bi.Size() || len(values) != bi.Size() {
+		t.Fatalf("got sizes %d, %d; want %d", len(keys), len(values), bi.Size())
+	}
+	for k, v := range bi.forward.Range {
+		if _, ok := keys[k]; !ok {
+			t.Errorf("key %d missing from key set", k)
+		}
+		if _, ok := values[v]; !ok {
+			t.Errorf("value %q missing from value set", v)
+		}
+	}
+	if _, ok := keys[4]; ok {
+		t.Errorf("key %d unexpectedly in key set", 4)
+	}
+	if _, ok := values["four"]; ok {
+		t.Errorf("value %q unexpectedly in value set", "four")
+	}
+}
+
+func TestApplyDiffWithCleanDiff(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	bi.Store(3, "three")
+	add := []Pair[int, string]{{1, "uno"}, {4, "two"}, {5, "five"}}
+	if ok := bi.ApplyDiff(add, []int{1, 2, 6}); !ok {
+		t.Fatalf("got %t; want %t", ok, true)
+	}
+	want := New[int, string]()
+	want.Store(1, "uno")
+	want.Store(3, "three")
+	want.Store(4, "two")
+	want.Store(5, "five")
+	if !bi.Equal(want) {
+		t.Errorf("got %v; want %v", bi, want)
+	}
+}
+
+func TestThatApplyDiffWithConflictingDiffRollsBack(t *testing.T) {
+	cases := []struct {
+		desc   string
+		add    []Pair[int, string]
+		remove []int
+	}{
+		{
+			desc:   "addition conflicting with a remaining key",
+			add:    []Pair[int, string]{{4, "four"}, {3, "tres"}},
+			remove: []int{1},
+		}, {
+			desc:   "addition conflicting with a remaining value",
+			add:    []Pair[int, string]{{4, "three"}},
+			remove: []int{1},
+		}, {
+			desc:   "additions conflicting with each other",
+			add:    []Pair[int, string]{{4, "four"}, {5, "four"}},
+			remove: nil,
+		},
+	}
+	for _, c := range cases {
+		bi := New[int, string]()
+		bi.Store(1, "one")
+		bi.Store(2, "two")
+		bi.Store(3, "three")
+		want := New[int, string]()
+		want.Store(1, "one")
+		want.Store(2, "two")
+		want.Store(3, "three")
+		if ok := bi.ApplyDiff(c.add, c.remove); ok {
+			t.Errorf("%s: got %t; want %t", c.desc, ok, false)
+		}
+		if !bi.Equal(want) {
+			t.Errorf("%s: got %v; want %v", c.desc, bi, want)
+		}
+	}
+}
+
+func TestMergeWithCombine(t *testing.T) {
+	bi := New[string, int]()
+	bi.Store("a", 1)
+	bi.Store("b", 2)
+	other := New[string, int]()
+	other.Store("b", 10)
+	other.Store("c", 3)
+	add := func(a, b int) int { return a + b }
+	if !bi.MergeWithCombine(other, add) {
+		t.Fatalf("got %t; want %t", false, true)
+	}
+	want := map[string]int{"a": 1, "b": 12, "c": 3}
+	if ok, details := AssertEqual(bi, want); !ok {
+		t.Errorf("got unexpected pairs:\n%s", details)
+	}
+}
+
+func TestThatMergeWithCombineRollsBackOnCollision(t *testing.T) {
+	cases := []struct {
+		desc  string
+		other map[string]int
+	}{
+		{
+			desc:  "combined value collides with a remaining value",
+			other: map[string]int{"b": -1}, // 2 + -1 == 1, a's value
+		}, {
+			desc:  "incoming value collides with a remaining value",
+			other: map[string]int{"c": 1},
+		},
+	}
+	for _, c := range cases {
+		bi := New[string, int]()
+		bi.Store("a", 1)
+		bi.Store("b", 2)
+		if bi.MergeWithCombine(MustFromMap(c.other), func(a, b int) int { return a + b }) {
+			t.Errorf("%s: got %t; want %t", c.desc, true, false)
+		}
+		want := map[string]int{"a": 1, "b": 2}
+		if ok, details := AssertEqual(bi, want); !ok {
+			t.Errorf("%s: got unexpected pairs:\n%s", c.desc, details)
+		}
+	}
+}
+
+func TestThatZeroValuedPairsSurviveDeletionsOfAbsentKeysAndValues(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(0, "zero")
+	bi.Store(1, "")
+	bi.DeleteByKey(2)    // would delete "" if the key's absence were ignored
+	bi.DeleteByValue("") // legitimately deletes 1:""
+	bi.DeleteByValue("two")
+	if size, n := bi.Size(), bi.inverse.Len(); size != 1 || n != 1 {
+		t.Fatalf("got sizes %d, %d; want 1, 1", size, n)
+	}
+	if v, exists := bi.LoadValue(0); !exists || v != "zero" {
+		t.Errorf("got %q, %t; want %q, %t", v, exists, "zero", true)
+	}
+	if k, exists := bi.LoadKey("zero"); !exists || k != 0 {
+		t.Errorf("got %d, %t; want %d, %t", k, exists, 0, true)
+	}
+}
+
+func TestThatTheZeroPairSurvivesDeletionsOfAbsentKeysAndValues(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(0, "")
+	bi.Store(1, "one")
+	bi.DeleteByKey(2)
+	bi.DeleteByValue("two")
+	if size, n := bi.Size(), bi.inverse.Len(); size != 2 || n != 2 {
+		t.Fatalf("got sizes %d, %d; want 2, 2", size, n)
+	}
+	if v, exists := bi.LoadValue(0); !exists || v != "" {
+		t.Errorf("got %q, %t; want %q, %t", v, exists, "", true)
+	}
+	if k, exists := bi.LoadKey(""); !exists || k != 0 {
+		t.Errorf("got %d, %t; want %d, %t", k, exists, 0, true)
+	}
+	bi.DeleteByKey(0)
+	if _, exists := bi.LoadKey(""); exists || bi.Size() != 1 {
+		t.Errorf("DeleteByKey(0) failed to delete the zero pair")
+	}
+}
+
+func TestThatAZeroValueSurvivesTheDeletionOfAnAbsentKey(t *testing.T) {
+	bi := New[string, int]()
+	bi.Store("zero", 0)
+	bi.DeleteByKey("one")
+	if k, exists := bi.LoadKey(0); !exists || k != "zero" {
+		t.Errorf("got %q, %t; want %q, %t", k, exists, "zero", true)
+	}
+	bi.DeleteByValue(1)
+	if v, exists := bi.LoadValue("zero"); !exists || v != 0 {
+		t.Errorf("got %d, %t; want %d, %t", v, exists, 0, true)
+	}
+}
+
+func TestFromSlicesWithEqualLengths(t *testing.T) {
+	bi, err := FromSlices([]int{1, 2, 3}, []string{"one", "two", "three"})
+	if err != nil {
+		t.Fatalf("got error %v; want nil", err)
+	}
+	want := New[int, string]()
+	want.Store(1, "one")
+	want.Store(2, "two")
+	want.Store(3, "three")
+	if !bi.Equal(want) {
+		t.Errorf("got %v; want %v", bi, want)
+	}
+}
+
+func TestThatFromSlicesRejectsInvalidInput(t *testing.T) {
+	cases := []struct {
+		desc   string
+		keys   []int
+		values []string
+	}{
+		{"unequal lengths", []int{1, 2}, []string{"one"}},
+		{"duplicate key", []int{1, 1}, []string{"one", "uno"}},
+		{"duplicate value", []int{1, 2}, []string{"one", "one"}},
+	}
+	for _, c := range cases {
+		bi, err := FromSlices(c.keys, c.values)
+		if err == nil || bi != nil {
+			t.Errorf("%s: got %v, %v; want nil, non-nil error", c.desc, bi, err)
+		}
+	}
+}
+
+func TestLastStoredTracksTheLatestSuccessfulStore(t *testing.T) {
+	bi := New[float64, string]()
+	if p, ok := bi.LastStored(); ok {
+		t.Errorf("got %v, %t; want %v, %t", p, ok, Pair[float64, string]{}, false)
+	}
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	want := Pair[float64, string]{2, "two"}
+	if p, ok := bi.LastStored(); !ok || p != want {
+		t.Errorf("got %v, %t; want %v, %t", p, ok, want, true)
+	}
+	bi.Store(math.NaN(), "NaN")
+	if p, ok := bi.LastStored(); !ok || p != want {
+		t.Errorf("got %v, %t; want %v, %t", p, ok, want, true)
+	}
+	bi.Store(1, "one")
+	want = Pair[float64, string]{1, "one"}
+	if p, ok := bi.LastStored(); !ok || p != want {
+		t.Errorf("got %v, %t; want %v, %t", p, ok, want, true)
+	}
+}
+
+func TestSameSize(t *testing.T) {
+	empty := New[int, string]()
+	one := New[int, string]()
+	one.Store(1, "one")
+	uno := New[int, string]()
+	uno.Store(1, "uno")
+	two := New[int, string]()
+	two.Store(1, "one")
+	two.Store(2, "two")
+	var nilBimap *Bimap[int, string]
+	cases := []struct {
+		desc string
+		x, y *Bimap[int, string]
+		want bool
+	}{
+		{"empty and empty", empty, new(Bimap[int, string]), true},
+		{"populated with the same size", one, uno, true},
+		{"populated with different sizes", one, two, false},
+		{"empty and populated", empty, one, false},
+		{"nil and empty", nilBimap, empty, true},
+		{"nil and populated", nilBimap, one, false},
+		{"nil and nil", nilBimap, nilBimap, true},
+	}
+	for _, c := range cases {
+		if got := c.x.SameSize(c.y); got != c.want {
+			t.Errorf("%s: got %t; want %t", c.desc, got, c.want)
+		}
+		if got := c.y.SameSize(c.x); got != c.want {
+			t.Errorf("%s (reversed): got %t; want %t", c.desc, got, c.want)
+		}
+	}
+}
+
+func TestGroupByParity(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	bi.Store(3, "three")
+	bi.Store(4, "four")
+	bi.Store(5, "five")
+	groups := GroupBy(bi, func(k int, _ string) bool { return k%2 == 0 })
+	if len(groups) != 2 {
+		t.Fatalf("got %d groups; want %d", len(groups), 2)
+	}
+	byKey := func(a, b Pair[int, string]) bool { return a.Key < b.Key }
+	even := groups[true]
+	slices.SortFunc(even, byKey)
+	wantEven := []Pair[int, string]{{2, "two"}, {4, "four"}}
+	if !slices.Equal(even, wantEven) {
+		t.Errorf("got %v; want %v", even, wantEven)
+	}
+	odd := groups[false]
+	slices.SortFunc(odd, byKey)
+	wantOdd := []Pair[int, string]{{1, "one"}, {3, "three"}, {5, "five"}}
+	if !slices.Equal(odd, wantOdd) {
+		t.Errorf("got %v; want %v", odd, wantOdd)
+	}
+}
+
+func TestIndexBy(t *testing.T) {
+	bi := New[string, int]()
+	bi.Store("one", 1)
+	bi.Store("two", 2)
+	bi.Store("three", 3)
+	got, ok := IndexBy(bi, func(k string, v int) string { return k + strconv.Itoa(v) })
+	if !ok {
+		t.Fatalf("injective index: got %t; want %t", ok, true)
+	}
+	want := map[string]Pair[string, int]{
+		"one1":   {"one", 1},
+		"two2":   {"two", 2},
+		"three3": {"three", 3},
+	}
+	if len(got) != len(want) {
+		t.Errorf("injective index: got %v; want %v", got, want)
+	}
+	for i, p := range want {
+		if got[i] != p {
+			t.Errorf("injective index: got %v at %q; want %v", got[i], i, p)
+		}
+	}
+	byLength := func(k string, _ int) int { return len(k) }
+	if got, ok := IndexBy(bi, byLength); ok || got != nil {
+		t.Errorf("colliding index: got %v, %t; want nil, %t", got, ok, false)
+	}
+}
+
+func TestRotateValuesAlongAThreeCycle(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	bi.Store(3, "three")
+	bi.Store(4, "four")
+	if ok := bi.RotateValues(1, 2, 3); !ok {
+		t.Fatalf("got %t; want %t", ok, true)
+	}
+	want := New[int, string]()
+	want.Store(1, "two")
+	want.Store(2, "three")
+	want.Store(3, "one")
+	want.Store(4, "four")
+	if !bi.Equal(want) {
+		t.Errorf("got %v; want %v", bi, want)
+	}
+	if k, exists := bi.LoadKey("one"); !exists || k != 3 {
+		t.Errorf("got %d, %t; want %d, %t", k, exists, 3, true)
+	}
+}
+
+func TestThatRotateValuesFailsAtomically(t *testing.T) {
+	cases := []struct {
+		desc string
+		keys []int
+	}{
+		{"missing key", []int{1, 2, 5}},
+		{"duplicate key", []int{1, 2, 1}},
+	}
+	for _, c := range cases {
+		bi := New[int, string]()
+		bi.Store(1, "one")
+		bi.Store(2, "two")
+		bi.Store(3, "three")
+		want := New[int, string]()
+		want.Store(1, "one")
+		want.Store(2, "two")
+		want.Store(3, "three")
+		if ok := bi.RotateValues(c.keys...); ok {
+			t.Errorf("%s: got %t; want %t", c.desc, ok, false)
+		}
+		if !bi.Equal(want) {
+			t.Errorf("%s: got %v; want %v", c.desc, bi, want)
+		}
+	}
+}
+
+func TestLoadValueOrElseOnlyComputesOnMiss(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	var calls int
+	compute := func() string {
+		calls++
+		return "default"
+	}
+	if v := bi.LoadValueOrElse(1, compute); v != "one" || calls != 0 {
+		t.Errorf("got %q, %d calls; want %q, %d calls", v, calls, "one", 0)
+	}
+	if v := bi.LoadValueOrElse(2, compute); v != "default" || calls != 1 {
+		t.Errorf("got %q, %d calls; want %q, %d calls", v, calls, "default", 1)
+	}
+	if _, exists := bi.LoadValue(2); exists {
+		t.Error("computed value was unexpectedly stored")
+	}
+}
+
+func TestSelectKeysSkipsAbsentKeys(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	bi.Store(3, "three")
+	got := bi.SelectKeys(1, 3, 4, 1)
+	want := New[int, string]()
+	want.Store(1, "one")
+	want.Store(3, "three")
+	if !got.Equal(want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+	got.Store(2, "two")
+	got.DeleteByKey(1)
+	if size := bi.Size(); size != 3 {
+		t.Errorf("bi.Size() = %d; want %d", size, 3)
+	}
+}
+
+func TestSelectValuesSkipsAbsentValues(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	bi.Store(3, "three")
+	got := bi.SelectValues("two", "four", "three")
+	want := New[int, string]()
+	want.Store(2, "two")
+	want.Store(3, "three")
+	if !got.Equal(want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+	if got := bi.SelectValues("four"); got.Size() != 0 {
+		t.Errorf("got %v; want empty bimap", got)
+	}
+}
+
+func TestAtLeast(t *testing.T) {
+	bi := New[int, string]()
+	for i, s := range []string{"zero", "one", "two", "three", "four", "five"} {
+		bi.Store(i, s)
+	}
+	isEven := func(k int, _ string) bool { return k%2 == 0 } // 3 matches
+	cases := []struct {
+		n     int
+		want  bool
+		calls int // maximum number of calls to pred
+	}{
+		{0, true, 0},
+		{2, true, 6},
+		{3, true, 6},
+		{4, false, 6},
+	}
+	for _, c := range cases {
+		var calls int
+		pred := func(k int, v string) bool {
+			calls++
+			return isEven(k, v)
+		}
+		if got := bi.AtLeast(c.n, pred); got != c.want {
+			t.Errorf("AtLeast(%d, isEven) = %t; want %t", c.n, got, c.want)
+		}
+		if calls > c.calls {
+			t.Errorf("AtLeast(%d, isEven): pred called %d times; want at most %d", c.n, calls, c.calls)
+		}
+	}
+}
+
+func TestThatAtLeastStopsEarly(t *testing.T) {
+	bi := New[int, string]()
+	for i, s := range []string{"zero", "one", "two", "three"} {
+		bi.Store(i, s)
+	}
+	var calls int
+	always := func(int, string) bool {
+		calls++
+		return true
+	}
+	if ok := bi.AtLeast(1, always); !ok || calls != 1 {
+		t.Errorf("got %t after %d calls; want %t after %d call", ok, calls, true, 1)
+	}
+}
+
+func TestDrainReturnsThePriorContentsAndEmptiesTheBimap(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	bi.Store(3, "three")
+	got := bi.Drain()
+	slices.SortFunc(got, func(a, b Pair[int, string]) bool {
+		return a.Key < b.Key
+	})
+	want := []Pair[int, string]{{1, "one"}, {2, "two"}, {3, "three"}}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+	if cap(got) != len(want) {
+		t.Errorf("got capacity %d; want %d", cap(got), len(want))
+	}
+	if size := bi.Size(); size != 0 {
+		t.Errorf("bi.Size() = %d; want %d", size, 0)
+	}
+	if _, exists := bi.LoadKey("one"); exists {
+		t.Errorf("value %q unexpectedly present", "one")
+	}
+	bi.Store(4, "four")
+	if v, exists := bi.LoadValue(4); !exists || v != "four" {
+		t.Errorf("got %q, %t; want %q, %t", v, exists, "four", true)
+	}
+}
+
+func TestSetMaxSize(t *testing.T) {
+	bi := New[int, string]()
+	bi.SetMaxSize(2)
+	if ok := bi.Store(1, "one"); !ok {
+		t.Errorf("below the limit: got %t; want %t", ok, true)
+	}
+	if ok := bi.Store(2, "two"); !ok {
+		t.Errorf("reaching the limit: got %t; want %t", ok, true)
+	}
+	if ok := bi.Store(3, "three"); ok {
+		t.Errorf("above the limit: got %t; want %t", ok, false)
+	}
+	if p, _ := bi.LastStored(); p.Key != 2 {
+		t.Errorf("rejected store unexpectedly recorded: %v", p)
+	}
+	if ok := bi.Store(1, "uno"); !ok {
+		t.Errorf("reassigning a key at the limit: got %t; want %t", ok, true)
+	}
+	if ok := bi.Store(3, "two"); !ok {
+		t.Errorf("reassigning a value at the limit: got %t; want %t", ok, true)
+	}
+	if ok := bi.Store(1, "two"); !ok {
+		t.Errorf("merging two pairs at the limit: got %t; want %t", ok, true)
+	}
+	if size := bi.Size(); size != 1 {
+		t.Errorf("bi.Size() = %d; want %d", size, 1)
+	}
+	if ok := bi.Store(4, "four"); !ok {
+		t.Errorf("below the limit again: got %t; want %t", ok, true)
+	}
+	bi.SetMaxSize(0)
+	if ok := bi.Store(5, "five"); !ok {
+		t.Errorf("unlimited: got %t; want %t", ok, true)
+	}
+}
+
+func TestThatCanStoreAllHonoursTheMaxSize(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.SetMaxSize(3)
+	pairs := []Pair[int, string]{{1, "one"}, {2, "two"}, {2, "two"}, {3, "three"}, {4, "four"}}
+	if ok, i := bi.CanStoreAll(pairs); ok || i != 4 {
+		t.Errorf("got %t, %d; want %t, %d", ok, i, false, 4)
+	}
+	if ok := bi.ApplyDiff(pairs, []int{1}); ok {
+		t.Errorf("got %t; want %t", ok, false)
+	}
+	if ok := bi.ApplyDiff(pairs[1:], []int{1}); !ok {
+		t.Errorf("got %t; want %t", ok, true)
+	}
+	if size := bi.Size(); size != 3 {
+		t.Errorf("bi.Size() = %d; want %d", size, 3)
+	}
+}
+
+func TestCompose(t *testing.T) {
+	f := New[int, string]()
+	f.Store(1, "one")
+	f.Store(2, "two")
+	f.Store(3, "three")
+	g := New[string, rune]()
+	g.Store("one", 'I')
+	g.Store("three", 'Ⅲ')
+	g.Store("four", 'Ⅳ')
+	got, ok := Compose(f, g)
+	if !ok {
+		t.Fatalf("got %t; want %t", ok, true)
+	}
+	want := New[int, rune]()
+	want.Store(1, 'I')
+	want.Store(3, 'Ⅲ')
+	if !got.Equal(want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+}
+
+func TestThatComposeDetectsNonInjectiveResults(t *testing.T) {
+	f := &Bimap[int, string]{ // corrupted: 1 and 2 both map to "one"
+		forward: builtinMap[int, string]{1: "one", 2: "one"},
+		inverse: builtinMap[string, int]{"one": 1},
+	}
+	g := New[string, bool]()
+	g.Store("one", true)
+	if got, ok := Compose(f, g); ok || got != nil {
+		t.Errorf("got %v, %t; want nil, %t", got, ok, false)
+	}
+}
+
+func TestCloneMapWithInjectiveTransform(t *testing.T) {
+	bi := New[string, int]()
+	bi.Store("one", 1)
+	bi.Store("two", 2)
+	got, ok := CloneMap(bi, strconv.Itoa)
+	if !ok {
+		t.Fatalf("got %t; want %t", ok, true)
+	}
+	want := map[string]string{"one": "1", "two": "2"}
+	if ok, details := AssertEqual(got, want); !ok {
+		t.Errorf("got unexpected pairs:\n%s", details)
+	}
+	got.Store("three", "3")
+	if ok, details := AssertEqual(bi, map[string]int{"one": 1, "two": 2}); !ok {
+		t.Errorf("original was modified:\n%s", details)
+	}
+}
+
+func TestCloneMapWithCollidingTransform(t *testing.T) {
+	bi := New[string, int]()
+	bi.Store("one", 1)
+	bi.Store("two", 2)
+	bi.Store("three", 3)
+	isOdd := func(n int) bool { return n%2 != 0 }
+	if got, ok := CloneMap(bi, isOdd); got != nil || ok {
+		t.Errorf("got %v, %t; want <nil>, %t", got, ok, false)
+	}
+	if ok, details := AssertEqual(bi, map[string]int{"one": 1, "two": 2, "three": 3}); !ok {
+		t.Errorf("original was modified:\n%s", details)
+	}
+}
+
+func TestLookupChain2(t *testing.T) {
+	first := New[int, string]()
+	first.Store(1, "one")
+	first.Store(2, "two")
+	second := New[string, rune]()
+	second.Store("one", 'I')
+	second.Store("three", 'Ⅲ')
+	cases := []struct {
+		desc string
+		a    int
+		want rune
+		ok   bool
+	}{
+		{desc: "full chain", a: 1, want: 'I', ok: true},
+		{desc: "miss in first", a: 3},
+		{desc: "miss in second", a: 2},
+	}
+	for _, c := range cases {
+		got, ok := LookupChain2(first, second, c.a)
+		if got != c.want || ok != c.ok {
+			t.Errorf("%s: got %q, %t; want %q, %t", c.desc, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestIsDisjoint(t *testing.T) {
+	cases := []struct {
+		desc string
+		x, y map[int]string
+		want bool
+	}{
+		{
+			desc: "fully disjoint",
+			x:    map[int]string{1: "one", 2: "two"},
+			y:    map[int]string{3: "three"},
+			want: true,
+		}, {
+			desc: "empty",
+			x:    map[int]string{1: "one"},
+			y:    map[int]string{},
+			want: true,
+		}, {
+			desc: "overlapping keys",
+			x:    map[int]string{1: "one", 2: "two"},
+			y:    map[int]string{2: "deux", 3: "three"},
+			want: false,
+		}, {
+			desc: "overlapping values",
+			x:    map[int]string{1: "one", 2: "two"},
+			y:    map[int]string{3: "two"},
+			want: false,
+		},
+	}
+	for _, c := range cases {
+		x := New[int, string]()
+		for k, v := range c.x {
+			x.Store(k, v)
+		}
+		y := New[int, string]()
+		for k, v := range c.y {
+			y.Store(k, v)
+		}
+		if got := x.IsDisjoint(y); got != c.want {
+			t.Errorf("%s: got %t; want %t", c.desc, got, c.want)
+		}
+		if got := y.IsDisjoint(x); got != c.want {
+			t.Errorf("%s (reversed): got %t; want %t", c.desc, got, c.want)
+		}
+	}
+}
+
+func TestThatLookupFunctionsReflectSubsequentStores(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	loadValue := bi.ValueLookup()
+	loadKey := bi.KeyLookup()
+	if v, ok := loadValue(1); !ok || v != "one" {
+		t.Errorf("got %q, %t; want %q, %t", v, ok, "one", true)
+	}
+	bi.Store(2, "two")
+	bi.Store(1, "uno")
+	if v, ok := loadValue(2); !ok || v != "two" {
+		t.Errorf("got %q, %t; want %q, %t", v, ok, "two", true)
+	}
+	if k, ok := loadKey("uno"); !ok || k != 1 {
+		t.Errorf("got %d, %t; want %d, %t", k, ok, 1, true)
+	}
+	if k, ok := loadKey("one"); ok {
+		t.Errorf("got %d, %t; want %d, %t", k, ok, 0, false)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	x := New[int, string]()
+	x.Store(1, "one")
+	x.Store(2, "two")
+	x.Store(3, "three")
+	x.Store(4, "four")
+	y := New[int, string]()
+	y.Store(1, "one")
+	y.Store(2, "deux")
+	y.Store(5, "five")
+	y.Store(6, "six")
+	added, removed, changed := x.Diff(y)
+	byKey := func(a, b Pair[int, string]) bool { return a.Key < b.Key }
+	slices.SortFunc(added, byKey)
+	slices.SortFunc(removed, byKey)
+	slices.SortFunc(changed, byKey)
+	wantAdded := []Pair[int, string]{{5, "five"}, {6, "six"}}
+	if !slices.Equal(added, wantAdded) {
+		t.Errorf("added: got %v; want %v", added, wantAdded)
+	}
+	wantRemoved := []Pair[int, string]{{3, "three"}, {4, "four"}}
+	if !slices.Equal(removed, wantRemoved) {
+		t.Errorf("removed: got %v; want %v", removed, wantRemoved)
+	}
+	wantChanged := []Pair[int, string]{{2, "deux"}}
+	if !slices.Equal(changed, wantChanged) {
+		t.Errorf("changed: got %v; want %v", changed, wantChanged)
+	}
+}
+
+func TestDiffOfIdenticalBimapsIsEmpty(t *testing.T) {
+	x := New[int, string]()
+	x.Store(1, "one")
+	y := New[int, string]()
+	y.Store(1, "one")
+	added, removed, changed := x.Diff(y)
+	if len(added) != 0 || len(removed) != 0 || len(changed) != 0 {
+		t.Errorf("got %v, %v, %v; want empty diff", added, removed, changed)
+	}
+}
+
+func TestNewValidated(t *testing.T) {
+	keyOK := func(k string) bool { return strings.HasPrefix(k, "id-") }
+	valOK := func(v int) bool { return v > 0 }
+	cases := []struct {
+		key   string
+		value int
+		want  bool
+	}{
+		{"id-1", 1, true},
+		{"1", 1, false},
+		{"id-1", -1, false},
+		{"1", -1, false},
+	}
+	for _, c := range cases {
+		bi := NewValidated(keyOK, valOK)
+		got := bi.Store(c.key, c.value)
+		if size := bi.Size(); got != c.want || (size == 1) != c.want {
+			t.Errorf("Store(%q, %d): got %t, %d; want %t", c.key, c.value, got, size, c.want)
+		}
+	}
+}
+
+func TestThatNewValidatedWithNilValidatorsOnlyRejectsNonReflexivePairs(t *testing.T) {
+	bi := NewValidated[float64, string](nil, nil)
+	if ok := bi.Store(0, ""); !ok {
+		t.Errorf("got %t; want %t", ok, true)
+	}
+	if ok := bi.Store(math.NaN(), "NaN"); ok {
+		t.Errorf("got %t; want %t", ok, false)
+	}
+}
+
+func TestPairsWhere(t *testing.T) {
+	bi := New[int, int]()
+	bi.Store(1, 2)
+	bi.Store(3, 3)
+	bi.Store(5, 4)
+	bi.Store(6, 7)
+	got := bi.PairsWhere(func(k, v int) bool { return k < v })
+	slices.SortFunc(got, func(a, b Pair[int, int]) bool {
+		return a.Key < b.Key
+	})
+	want := []Pair[int, int]{{1, 2}, {6, 7}}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+}
+
+func TestDistanceTo(t *testing.T) {
+	cases := []struct {
+		desc string
+		x, y map[int]string
+		want int
+	}{
+		{
+			desc: "identical",
+			x:    map[int]string{1: "one", 2: "two"},
+			y:    map[int]string{1: "one", 2: "two"},
+			want: 0,
+		}, {
+			desc: "partially overlapping",
+			x:    map[int]string{1: "one", 2: "two", 3: "three"},
+			y:    map[int]string{1: "one", 2: "deux", 4: "four"},
+			want: 4,
+		}, {
+			desc: "disjoint",
+			x:    map[int]string{1: "one", 2: "two"},
+			y:    map[int]string{3: "three"},
+			want: 3,
+		},
+	}
+	for _, c := range cases {
+		x := New[int, string]()
+		for k, v := range c.x {
+			x.Store(k, v)
+		}
+		y := New[int, string]()
+		for k, v := range c.y {
+			y.Store(k, v)
+		}
+		if got := x.DistanceTo(y); got != c.want {
+			t.Errorf("%s: got %d; want %d", c.desc, got, c.want)
+		}
+		if got := y.DistanceTo(x); got != c.want {
+			t.Errorf("%s (reversed): got %d; want %d", c.desc, got, c.want)
+		}
+	}
+}
+
+func TestFromRangeWithInjectiveFunction(t *testing.T) {
+	names := []string{"zero", "one", "two"}
+	bi, ok := FromRange(len(names), func(i int) string { return names[i] })
+	if !ok {
+		t.Fatalf("got %t; want %t", ok, true)
+	}
+	want := New[int, string]()
+	want.Store(0, "zero")
+	want.Store(1, "one")
+	want.Store(2, "two")
+	if !bi.Equal(want) {
+		t.Errorf("got %v; want %v", bi, want)
+	}
+}
+
+func TestFromRangeWithCollidingFunction(t *testing.T) {
+	bi, ok := FromRange(4, func(i int) int { return i / 2 })
+	if ok || bi != nil {
+		t.Errorf("got %v, %t; want nil, %t", bi, ok, false)
+	}
+}
+
+func TestTransformValuesWithInjectiveTransform(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, " one")
+	bi.Store(2, "two ")
+	if ok := bi.TransformValues(strings.TrimSpace); !ok {
+		t.Fatalf("got %t; want %t", ok, true)
+	}
+	want := New[int, string]()
+	want.Store(1, "one")
+	want.Store(2, "two")
+	if !bi.Equal(want) {
+		t.Errorf("got %v; want %v", bi, want)
+	}
+	if k, exists := bi.LoadKey("two"); !exists || k != 2 {
+		t.Errorf("got %d, %t; want %d, %t", k, exists, 2, true)
+	}
+	if _, exists := bi.LoadKey("two "); exists {
+		t.Errorf("stale value %q unexpectedly present", "two ")
+	}
+}
+
+func TestThatTransformValuesLeavesTheBimapUnchangedOnCollision(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "one ")
+	want := New[int, string]()
+	want.Store(1, "one")
+	want.Store(2, "one ")
+	if ok := bi.TransformValues(strings.TrimSpace); ok {
+		t.Errorf("got %t; want %t", ok, false)
+	}
+	if !bi.Equal(want) || !CheckRoundTrip(bi) {
+		t.Errorf("got %v; want %v", bi, want)
+	}
+}
+
+func TestThatTransformValuesIgnoresAnExceededMaxSize(t *testing.T) {
+	bi := New[string, int]()
+	for i, k := range []string{"a", "b", "c", "d", "e"} {
+		bi.Store(k, i)
+	}
+	bi.SetMaxSize(2)
+	if !bi.TransformValues(func(v int) int { return v + 100 }) {
+		t.Fatalf("got %t; want %t", false, true)
+	}
+	want := map[string]int{"a": 100, "b": 101, "c": 102, "d": 103, "e": 104}
+	if ok, details := AssertEqual(bi, want); !ok {
+		t.Errorf("got unexpected pairs:\n%s", details)
+	}
+}
+
+func TestTransformKeysWithInjectiveTransform(t *testing.T) {
+	bi := New[string, int]()
+	bi.Store("One", 1)
+	bi.Store("TWO", 2)
+	if ok := bi.TransformKeys(strings.ToLower); !ok {
+		t.Fatalf("got %t; want %t", ok, true)
+	}
+	want := New[string, int]()
+	want.Store("one", 1)
+	want.Store("two", 2)
+	if !bi.Equal(want) {
+		t.Errorf("got %v; want %v", bi, want)
+	}
+	if k, exists := bi.LoadKey(2); !exists || k != "two" {
+		t.Errorf("got %q, %t; want %q, %t", k, exists, "two", true)
+	}
+}
+
+func TestThatTransformKeysLeavesTheBimapUnchangedOnCollision(t *testing.T) {
+	bi := New[string, int]()
+	bi.Store("one", 1)
+	bi.Store("ONE", 2)
+	want := New[string, int]()
+	want.Store("one", 1)
+	want.Store("ONE", 2)
+	if ok := bi.TransformKeys(strings.ToLower); ok {
+		t.Errorf("got %t; want %t", ok, false)
+	}
+	if !bi.Equal(want) || !CheckRoundTrip(bi) {
+		t.Errorf("got %v; want %v", bi, want)
+	}
+}
+
+func TestThatTransformKeysIgnoresAnExceededMaxSize(t *testing.T) {
+	bi := New[int, string]()
+	for i, v := range []string{"a", "b", "c", "d", "e"} {
+		bi.Store(i, v)
+	}
+	bi.SetMaxSize(2)
+	if !bi.TransformKeys(func(k int) int { return k + 100 }) {
+		t.Fatalf("got %t; want %t", false, true)
+	}
+	want := map[int]string{100: "a", 101: "b", 102: "c", 103: "d", 104: "e"}
+	if ok, details := AssertEqual(bi, want); !ok {
+		t.Errorf("got unexpected pairs:\n%s", details)
+	}
+}
+
+func TestOnly(t *testing.T) {
+	bi := New[int, string]()
+	if p, ok := bi.Only(); ok {
+		t.Errorf("empty: got %v, %t; want %v, %t", p, ok, Pair[int, string]{}, false)
+	}
+	bi.Store(1, "one")
+	want := Pair[int, string]{1, "one"}
+	if p, ok := bi.Only(); !ok || p != want {
+		t.Errorf("one pair: got %v, %t; want %v, %t", p, ok, want, true)
+	}
+	bi.Store(2, "two")
+	if p, ok := bi.Only(); ok {
+		t.Errorf("many pairs: got %v, %t; want %v, %t", p, ok, Pair[int, string]{}, false)
+	}
+}
+
+func TestMustFromMap(t *testing.T) {
+	bi := MustFromMap(map[int]string{1: "one", 2: "two"})
+	want := New[int, string]()
+	want.Store(1, "one")
+	want.Store(2, "two")
+	if !bi.Equal(want) {
+		t.Errorf("got %v; want %v", bi, want)
+	}
+}
+
+func TestThatMustFromMapPanicsOnDuplicateValues(t *testing.T) {
+	defer func() {
+		r := recover()
+		if msg, ok := r.(string); !ok || !strings.Contains(msg, "duplicate value one") {
+			t.Errorf("got panic %v; want a panic about a duplicate value", r)
+		}
+	}()
+	MustFromMap(map[int]string{1: "one", 2: "one"})
+}
+
+func TestBatches(t *testing.T) {
+	bi := New[int, string]()
+	for i := 0; i < 7; i++ {
+		bi.Store(i, strconv.Itoa(i))
+	}
+	var sizes []int
+	seen := make(map[int]int)
+	for batch := range Batches(bi, 3) {
+		sizes = append(sizes, len(batch))
+		for _, p := range batch {
+			seen[p.Key]++
+			if v, _ := bi.LoadValue(p.Key); v != p.Value {
+				t.Errorf("got pair %v; want %d:%s", p, p.Key, v)
+			}
+		}
+	}
+	if want := []int{3, 3, 1}; !slices.Equal(sizes, want) {
+		t.Errorf("got batch sizes %v; want %v", sizes, want)
+	}
+	for i := 0; i < 7; i++ {
+		if seen[i] != 1 {
+			t.Errorf("key %d seen %d times; want once", i, seen[i])
+		}
+	}
+}
+
+func TestBatchesWithAHugeSize(t *testing.T) {
+	bi := New[int, string]()
+	for i := 0; i < 5; i++ {
+		bi.Store(i, strconv.Itoa(i))
+	}
+	var sizes []int
+	for batch := range Batches(bi, math.MaxInt) {
+		sizes = append(sizes, len(batch))
+	}
+	if want := []int{5}; !slices.Equal(sizes, want) {
+		t.Errorf("got batch sizes %v; want %v", sizes, want)
+	}
+}
+
+func TestBatchesSupportsEarlyTermination(t *testing.T) {
+	bi := New[int, string]()
+	for i := 0; i < 6; i++ {
+		bi.Store(i, strconv.Itoa(i))
+	}
+	var n int
+	for range Batches(bi, 2) {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("got %d batches; want %d", n, 1)
+	}
+}
+
+func TestPopulateKeyFilter(t *testing.T) {
+	bi := New[int, string]()
+	for i := 0; i < 5; i++ {
+		bi.Store(i, strconv.Itoa(i))
+	}
+	seen := make(map[int]int)
+	PopulateKeyFilter(bi, func(k int) { seen[k]++ })
+	if len(seen) != bi.Size() {
+		t.Errorf("got %d distinct keys; want %d", len(seen), bi.Size())
+	}
+	for k, n := range seen {
+		if n != 1 {
+			t.Errorf("key %d added %d times; want once", k, n)
+		}
+	}
+}
+
+func TestPopulateValueFilter(t *testing.T) {
+	bi := New[int, string]()
+	for i := 0; i < 5; i++ {
+		bi.Store(i, strconv.Itoa(i))
+	}
+	seen := make(map[string]int)
+	PopulateValueFilter(bi, func(v string) { seen[v]++ })
+	if len(seen) != bi.Size() {
+		t.Errorf("got %d distinct values; want %d", len(seen), bi.Size())
+	}
+	for v, n := range seen {
+		if n != 1 {
+			t.Errorf("value %q added %d times; want once", v, n)
+		}
+	}
+}
+
+func TestThatReadsOnANilBimapBehaveAsOnAnEmptyBimap(t *testing.T) {
+	var bi *Bimap[int, string]
+	if v, ok := bi.LoadValue(1); v != "" || ok {
+		t.Errorf("LoadValue: got %q, %t; want %q, %t", v, ok, "", false)
+	}
+	if k, ok := bi.LoadKey("one"); k != 0 || ok {
+		t.Errorf("LoadKey: got %d, %t; want %d, %t", k, ok, 0, false)
+	}
+	if got := bi.Size(); got != 0 {
+		t.Errorf("Size: got %d; want %d", got, 0)
+	}
+	if got := bi.Keys(); len(got) != 0 {
+		t.Errorf("Keys: got %v; want none", got)
+	}
+	if got, want := bi.String(), "Bimap[]"; got != want {
+		t.Errorf("String: got %q; want %q", got, want)
+	}
+	if !bi.Equal(New[int, string]()) || !New[int, string]().Equal(bi) {
+		t.Error("Equal: got false; want true")
+	}
+	if ok, i := bi.CanStoreAll([]Pair[int, string]{{1, "one"}}); !ok || i != -1 {
+		t.Errorf("CanStoreAll: got %t, %d; want %t, %d", ok, i, true, -1)
+	}
+	bi.DeleteByKey(1)
+	bi.DeleteByValue("one")
+	if got := bi.Retain(func(int, string) bool { return false }); got != 0 {
+		t.Errorf("Retain: got %d; want %d", got, 0)
+	}
+}
+
+func TestThatStoreOnANilBimapPanicsWithAnExplicitMessage(t *testing.T) {
+	defer func() {
+		r := recover()
+		const want = "bimap: Store called on nil *Bimap"
+		if msg, ok := r.(string); !ok || msg != want {
+			t.Errorf("got panic %v; want %q", r, want)
+		}
+	}()
+	var bi *Bimap[int, string]
+	bi.Store(1, "one")
+}
+
+func squares(n int) func(func(int, int) bool) {
+	return func(yield func(int, int) bool) {
+		for i := 0; i < n; i++ {
+			if !yield(i, i*i) {
+				return
+			}
+		}
+	}
+}
+
+func TestFromSeq2WithSize(t *testing.T) {
+	bi, ok := FromSeq2WithSize(squares(4), 4)
+	if !ok {
+		t.Fatalf("got %t; want %t", ok, true)
+	}
+	want := map[int]int{0: 0, 1: 1, 2: 4, 3: 9}
+	if ok, details := AssertEqual(bi, want); !ok {
+		t.Errorf("got unexpected pairs:\n%s", details)
+	}
+	dup := func(yield func(int, string) bool) {
+		_ = yield(1, "one") && yield(2, "one")
+	}
+	if bi, ok := FromSeq2WithSize(dup, 2); bi != nil || ok {
+		t.Errorf("duplicate values: got %v, %t; want <nil>, %t", bi, ok, false)
+	}
+}
+
+func BenchmarkFromSeq2WithSize(b *testing.B) {
+	const n = 10_000
+	b.Run("unsized", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = FromSeq2WithSize(squares(n), 0)
+		}
+	})
+	b.Run("sized", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = FromSeq2WithSize(squares(n), n)
+		}
+	})
+}
+
+func TestLoadValuesInto(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	dst := []string{"stale", "stale", "stale", "stale"}
+	n, missing := bi.LoadValuesInto(dst, []int{2, 3, 1})
+	if n != 2 || !slices.Equal(missing, []int{3}) {
+		t.Errorf("got %d, %v; want %d, %v", n, missing, 2, []int{3})
+	}
+	want := []string{"two", "", "one", "stale"}
+	if !slices.Equal(dst, want) {
+		t.Errorf("got %q; want %q", dst, want)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		bi.LoadValuesInto(dst, []int{1, 2})
+	})
+	if allocs != 0 {
+		t.Errorf("got %v allocations; want %d", allocs, 0)
+	}
+}
+
+func TestThatLoadValuesIntoPanicsIfDstIsTooShort(t *testing.T) {
+	defer func() {
+		r := recover()
+		const want = "bimap: dst too short (1) for keys (2)"
+		if msg, ok := r.(string); !ok || msg != want {
+			t.Errorf("got panic %v; want %q", r, want)
+		}
+	}()
+	bi := New[int, string]()
+	bi.LoadValuesInto(make([]string, 1), []int{1, 2})
+}
+
+func TestOverlapStats(t *testing.T) {
+	bi := New[string, int]()
+	bi.Store("a", 1)
+	bi.Store("b", 2)
+	bi.Store("c", 3)
+	bi.Store("d", 4)
+	other := New[string, int]()
+	other.Store("a", 1)  // exact match
+	other.Store("b", 20) // shared key, different value
+	other.Store("x", 3)  // shared value, different key
+	want := Overlap{SharedKeys: 2, SharedValues: 2, ExactMatches: 1}
+	if got := bi.OverlapStats(other); got != want {
+		t.Errorf("got %+v; want %+v", got, want)
+	}
+	if got := other.OverlapStats(bi); got != want {
+		t.Errorf("reversed: got %+v; want %+v", got, want)
+	}
+	if got := bi.OverlapStats(New[string, int]()); got != (Overlap{}) {
+		t.Errorf("empty: got %+v; want %+v", got, Overlap{})
+	}
+}
+
+func TestAssignValuesWithSequentialGenerator(t *testing.T) {
+	names := []string{"alice", "bob", "carol"}
+	code := func(_ string, i int) int { return 100 + i }
+	bi, ok := AssignValues(names, code)
+	if !ok {
+		t.Fatalf("got %t; want %t", ok, true)
+	}
+	want := map[string]int{"alice": 100, "bob": 101, "carol": 102}
+	if ok, details := AssertEqual(bi, want); !ok {
+		t.Errorf("got unexpected pairs:\n%s", details)
+	}
+}
+
+func TestAssignValuesWithCollidingGenerator(t *testing.T) {
+	names := []string{"alice", "bob", "carol"}
+	initial := func(name string, _ int) byte { return name[0] }
+	if bi, ok := AssignValues([]string{"alice", "bob"}, initial); !ok || bi.Size() != 2 {
+		t.Errorf("distinct initials: got %v, %t; want a bimap of size 2, %t", bi, ok, true)
+	}
+	if bi, ok := AssignValues(append(names, "abe"), initial); bi != nil || ok {
+		t.Errorf("colliding initials: got %v, %t; want <nil>, %t", bi, ok, false)
+	}
+}
+
+func TestStoreDelta(t *testing.T) {
+	cases := []struct {
+		desc      string
+		key       int
+		value     float64
+		wantDelta int
+		wantOK    bool
+	}{
+		{desc: "new pair", key: 3, value: 3.0, wantDelta: 1, wantOK: true},
+		{desc: "existing pair", key: 1, value: 1.0, wantDelta: 0, wantOK: true},
+		{desc: "existing key only", key: 1, value: 1.5, wantDelta: 0, wantOK: true},
+		{desc: "existing value only", key: 4, value: 2.0, wantDelta: 0, wantOK: true},
+		{desc: "key and value in distinct pairs", key: 1, value: 2.0, wantDelta: -1, wantOK: true},
+		{desc: "NaN value", key: 3, value: math.NaN(), wantDelta: 0, wantOK: false},
+	}
+	for _, c := range cases {
+		bi := New[int, float64]()
+		bi.Store(1, 1.0)
+		bi.Store(2, 2.0)
+		delta, ok := bi.StoreDelta(c.key, c.value)
+		if delta != c.wantDelta || ok != c.wantOK {
+			t.Errorf("%s: got %d, %t; want %d, %t", c.desc, delta, ok, c.wantDelta, c.wantOK)
+		}
+	}
+}
+
+func TestIndexedRange(t *testing.T) {
+	bi := New[int, string]()
+	for i := 0; i < 10; i++ {
+		bi.Store(i, strconv.Itoa(i))
+	}
+	var indices []int
+	seen := make(map[int]struct{})
+	bi.IndexedRange(func(i, k int, v string) bool {
+		indices = append(indices, i)
+		seen[k] = struct{}{}
+		if w, _ := bi.LoadValue(k); w != v {
+			t.Errorf("got pair %d:%s; want %d:%s", k, v, k, w)
+		}
+		return true
+	})
+	for i, idx := range indices {
+		if idx != i {
+			t.Fatalf("got indices %v; want 0 through %d", indices, bi.Size()-1)
+		}
+	}
+	if len(indices) != bi.Size() || len(seen) != bi.Size() {
+		t.Errorf("got %d indices over %d keys; want %d", len(indices), len(seen), bi.Size())
+	}
+}
+
+func TestIndexedRangeSupportsEarlyTermination(t *testing.T) {
+	bi := New[int, string]()
+	for i := 0; i < 10; i++ {
+		bi.Store(i, strconv.Itoa(i))
+	}
+	var last int
+	bi.IndexedRange(func(i, _ int, _ string) bool {
+		last = i
+		return i < 3
+	})
+	if last != 3 {
+		t.Errorf("got last index %d; want %d", last, 3)
+	}
+}
+
+func TestCombinedInverse(t *testing.T) {
+	en := MustFromMap(map[int]string{1: "one", 2: "two", 3: "three"})
+	fr := MustFromMap(map[int]string{1: "un", 2: "deux", 3: "trois"})
+	mixed := MustFromMap(map[int]string{10: "one", 20: "deux"})
+	got := CombinedInverse(map[string]*Bimap[int, string]{
+		"en":    en,
+		"fr":    fr,
+		"mixed": mixed,
+		"empty": New[int, string](),
+	})
+	want := map[string][]Occurrence[int]{
+		"one":   {{"en", 1}, {"mixed", 10}},
+		"two":   {{"en", 2}},
+		"three": {{"en", 3}},
+		"un":    {{"fr", 1}},
+		"deux":  {{"fr", 2}, {"mixed", 20}},
+		"trois": {{"fr", 3}},
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d values; want %d", len(got), len(want))
+	}
+	for v, occs := range want {
+		if !slices.Equal(got[v], occs) {
+			t.Errorf("%q: got %v; want %v", v, got[v], occs)
+		}
+	}
+}
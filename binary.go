@@ -0,0 +1,118 @@
+// Copyright 2020 Julien Cretel (jub0bs). All rights reserved.
+// Use of this source code is governed by a BSD 3-clause
+// license that can be found in the LICENSE file.
+
+package bimap
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+var errTruncated = errors.New("bimap: truncated binary data")
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// The encoding consists of the number of key-value pairs (as a
+// uvarint) followed by each pair's key and value.
+// Keys and values whose type or pointer type implements
+// encoding.BinaryMarshaler are encoded by their MarshalBinary method, prefixed by their
+// length (as a uvarint); other keys and values must be of a
+// fixed-size type, as defined by package encoding/binary, and are
+// encoded in little-endian byte order. In particular, neither int
+// nor string is supported.
+func (bi *Bimap[K, V]) MarshalBinary() ([]byte, error) {
+	buf := binary.AppendUvarint(nil, uint64(bi.fwd().Len()))
+	var err error
+	for k, v := range bi.fwd().Range {
+		if buf, err = appendBinary(buf, k); err != nil {
+			return nil, err
+		}
+		if buf, err = appendBinary(buf, v); err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler
+// interface. It expects data in the format produced by
+// MarshalBinary, except that keys and values whose pointer type
+// implements encoding.BinaryUnmarshaler are decoded by their
+// UnmarshalBinary method. UnmarshalBinary returns an error,
+// and leaves the Bimap unchanged, if data is malformed or if the
+// decoded pairs do not form a one-to-one correspondence or contain
+// keys or values that Store would reject. UnmarshalBinary panics
+// if bi is nil.
+func (bi *Bimap[K, V]) UnmarshalBinary(data []byte) error {
+	bi.mustBeNonNil("UnmarshalBinary")
+	count, n := binary.Uvarint(data)
+	if n <= 0 {
+		return errTruncated
+	}
+	data = data[n:]
+	forward, inverse := bi.newBackings(int(min(count, uint64(len(data)))))
+	for i := uint64(0); i < count; i++ {
+		var k K
+		var v V
+		n, err := readBinary(data, &k)
+		if err != nil {
+			return err
+		}
+		data = data[n:]
+		if n, err = readBinary(data, &v); err != nil {
+			return err
+		}
+		data = data[n:]
+		if err := bi.insertUnique(forward, inverse, k, v); err != nil {
+			return err
+		}
+	}
+	if len(data) != 0 {
+		return errors.New("bimap: trailing binary data")
+	}
+	old := bi.fwd()
+	bi.setBackings(forward, inverse)
+	bi.notifyReplaced(old)
+	return nil
+}
+
+func appendBinary[T any](buf []byte, t T) ([]byte, error) {
+	// Checking &t rather than t also catches pointer receivers, just
+	// like readBinary does for encoding.BinaryUnmarshaler.
+	if m, ok := any(&t).(encoding.BinaryMarshaler); ok {
+		data, err := m.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		buf = binary.AppendUvarint(buf, uint64(len(data)))
+		return append(buf, data...), nil
+	}
+	if binary.Size(t) < 0 {
+		return nil, fmt.Errorf("bimap: unsupported type %T for binary encoding", t)
+	}
+	return binary.Append(buf, binary.LittleEndian, t)
+}
+
+func readBinary[T any](data []byte, t *T) (int, error) {
+	if u, ok := any(t).(encoding.BinaryUnmarshaler); ok {
+		size, n := binary.Uvarint(data)
+		if n <= 0 || uint64(len(data)-n) < size {
+			return 0, errTruncated
+		}
+		end := n + int(size)
+		if err := u.UnmarshalBinary(data[n:end]); err != nil {
+			return 0, err
+		}
+		return end, nil
+	}
+	if binary.Size(*t) < 0 {
+		return 0, fmt.Errorf("bimap: unsupported type %T for binary encoding", *t)
+	}
+	n, err := binary.Decode(data, binary.LittleEndian, t)
+	if err != nil {
+		return 0, errTruncated
+	}
+	return n, nil
+}
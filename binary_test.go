@@ -0,0 +1,108 @@
+package bimap
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBinaryRoundTripWithFixedSizeTypes(t *testing.T) {
+	bi := New[int32, float64]()
+	bi.Store(1, 1.5)
+	bi.Store(-2, 2.25)
+	bi.Store(3, -3)
+	data, err := bi.MarshalBinary()
+	if err != nil {
+		t.Fatalf("got error %v; want nil", err)
+	}
+	got := New[int32, float64]()
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatalf("got error %v; want nil", err)
+	}
+	if !got.Equal(bi) {
+		t.Errorf("got %v; want %v", got, bi)
+	}
+}
+
+func TestBinaryRoundTripWithBinaryMarshalers(t *testing.T) {
+	bi := New[time.Time, uint8]()
+	bi.Store(time.Date(2020, 7, 21, 0, 0, 0, 0, time.UTC), 1)
+	bi.Store(time.Date(2022, 4, 3, 12, 0, 0, 0, time.UTC), 2)
+	data, err := bi.MarshalBinary()
+	if err != nil {
+		t.Fatalf("got error %v; want nil", err)
+	}
+	var got Bimap[time.Time, uint8]
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatalf("got error %v; want nil", err)
+	}
+	if !got.Equal(bi) {
+		t.Errorf("got %v; want %v", &got, bi)
+	}
+}
+
+// point has pointer-receiver binary marshaling methods that encode
+// it in big-endian byte order, unlike the default encoding.
+type point struct{ X, Y uint32 }
+
+func (p *point) MarshalBinary() ([]byte, error) {
+	return binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32(nil, p.X), p.Y), nil
+}
+
+func (p *point) UnmarshalBinary(data []byte) error {
+	if len(data) != 8 {
+		return errors.New("point: invalid length")
+	}
+	p.X = binary.BigEndian.Uint32(data)
+	p.Y = binary.BigEndian.Uint32(data[4:])
+	return nil
+}
+
+func TestBinaryRoundTripWithPointerReceiverMarshalers(t *testing.T) {
+	bi := New[point, uint8]()
+	bi.Store(point{1, 2}, 7)
+	bi.Store(point{3, 4}, 8)
+	data, err := bi.MarshalBinary()
+	if err != nil {
+		t.Fatalf("got error %v; want nil", err)
+	}
+	var got Bimap[point, uint8]
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatalf("got error %v; want nil", err)
+	}
+	if !got.Equal(bi) {
+		t.Errorf("got %v; want %v", &got, bi)
+	}
+}
+
+func TestThatMarshalBinaryRejectsUnsupportedTypes(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	if _, err := bi.MarshalBinary(); err == nil {
+		t.Error("got nil error; want non-nil error")
+	}
+}
+
+func TestThatUnmarshalBinaryRejectsInvalidData(t *testing.T) {
+	cases := []struct {
+		desc string
+		data []byte
+	}{
+		{"empty", nil},
+		{"truncated", []byte{1, 1}},
+		{"trailing data", []byte{1, 1, 2, 0}},
+		{"duplicate key", []byte{2, 1, 2, 1, 3}},
+		{"duplicate value", []byte{2, 1, 2, 3, 2}},
+	}
+	for _, c := range cases {
+		bi := New[uint8, uint8]()
+		bi.Store(42, 42)
+		if err := bi.UnmarshalBinary(c.data); err == nil {
+			t.Errorf("%s: got nil error; want non-nil error", c.desc)
+		}
+		if v, exists := bi.LoadValue(42); bi.Size() != 1 || !exists || v != 42 {
+			t.Errorf("%s: bimap unexpectedly modified: %v", c.desc, bi)
+		}
+	}
+}
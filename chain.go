@@ -0,0 +1,107 @@
+// Copyright 2020 Julien Cretel (jub0bs). All rights reserved.
+// Use of this source code is governed by a BSD 3-clause
+// license that can be found in the LICENSE file.
+
+package bimap
+
+// Neighbors returns the predecessor and successor of x in bi,
+// where bi is viewed as a chain in which each key links to its
+// value. The hasPrev and hasNext results indicate whether x is a
+// value and a key of bi, respectively.
+func Neighbors[T comparable](bi *Bimap[T, T], x T) (prev T, hasPrev bool, next T, hasNext bool) {
+	prev, hasPrev = bi.LoadKey(x)
+	next, hasNext = bi.LoadValue(x)
+	return
+}
+
+// Advance follows the links of bi, viewed as a chain in which each
+// key links to its value, the given number of steps from start.
+// A negative number of steps follows the links backwards, from
+// values to keys. The ok result is false if the chain ends before
+// the last step.
+func Advance[T comparable](bi *Bimap[T, T], start T, steps int) (T, bool) {
+	load, n := bi.LoadValue, uint(steps)
+	if steps < 0 {
+		load = bi.LoadKey
+		n = -n // unlike -steps, doesn't overflow for math.MinInt
+	}
+	x := start
+	for ; n > 0; n-- {
+		next, ok := load(x)
+		if !ok {
+			var zero T
+			return zero, false
+		}
+		x = next
+	}
+	return x, true
+}
+
+// IsInternalNode reports whether x is both a key and a value of
+// bi, i.e. whether x has both a predecessor and a successor in
+// bi viewed as a chain in which each key links to its value.
+func IsInternalNode[T comparable](bi *Bimap[T, T], x T) bool {
+	_, hasPrev, _, hasNext := Neighbors(bi, x)
+	return hasPrev && hasNext
+}
+
+// LongestChain returns the length, in key-value pairs, of the
+// longest chain of bi, where bi is viewed as a set of disjoint
+// chains in which each key links to its value. A chain that loops
+// back on itself (a cycle) counts each of its pairs once; in
+// particular, a key associated with itself forms a chain of length 1.
+// The complexity is O(n), where n is the size of bi.
+func LongestChain[T comparable](bi *Bimap[T, T]) int {
+	visited := make(map[T]struct{}, bi.Size())
+	var longest int
+	for k := range bi.fwd().Range {
+		if _, hasPrev := bi.LoadKey(k); hasPrev {
+			continue // k isn't the start of an open chain
+		}
+		var n int
+		for x, ok := k, true; ok; x, ok = bi.LoadValue(x) {
+			visited[x] = struct{}{}
+			n++
+		}
+		longest = max(longest, n-1) // the last node isn't a key
+	}
+	for k := range bi.fwd().Range { // remaining keys belong to cycles
+		if contains(visited, k) {
+			continue
+		}
+		var n int
+		for x := k; !contains(visited, x); x, _ = bi.LoadValue(x) {
+			visited[x] = struct{}{}
+			n++
+		}
+		longest = max(longest, n)
+	}
+	return longest
+}
+
+// AdvanceSafe behaves like Advance, except that it never takes
+// more steps than there are pairs in the chain that contains start:
+// if the links of bi lead back to start, AdvanceSafe skips the full
+// turns of that cycle, so that even a huge number of steps
+// completes quickly. The completed result is false if the chain
+// ends before the last step.
+func AdvanceSafe[T comparable](bi *Bimap[T, T], start T, steps int) (result T, completed bool) {
+	load, n := bi.LoadValue, uint(steps)
+	if steps < 0 {
+		load = bi.LoadKey
+		n = -n // unlike -steps, doesn't overflow for math.MinInt
+	}
+	x := start
+	for taken := uint(1); taken <= n; taken++ {
+		next, ok := load(x)
+		if !ok {
+			var zero T
+			return zero, false
+		}
+		x = next
+		if x == start { // start lies on a cycle of length taken
+			n = taken + (n-taken)%taken
+		}
+	}
+	return x, true
+}
@@ -0,0 +1,160 @@
+package bimap
+
+import (
+	"math"
+	"testing"
+)
+
+func newChain() *Bimap[string, string] {
+	// a -> b -> c -> d
+	bi := New[string, string]()
+	bi.Store("a", "b")
+	bi.Store("b", "c")
+	bi.Store("c", "d")
+	return bi
+}
+
+func TestNeighbors(t *testing.T) {
+	bi := newChain()
+	cases := []struct {
+		x       string
+		prev    string
+		hasPrev bool
+		next    string
+		hasNext bool
+	}{
+		{"a", "", false, "b", true},
+		{"b", "a", true, "c", true},
+		{"d", "c", true, "", false},
+		{"z", "", false, "", false},
+	}
+	for _, c := range cases {
+		prev, hasPrev, next, hasNext := Neighbors(bi, c.x)
+		if prev != c.prev || hasPrev != c.hasPrev || next != c.next || hasNext != c.hasNext {
+			t.Errorf(
+				"Neighbors(bi, %q): got %q, %t, %q, %t; want %q, %t, %q, %t",
+				c.x, prev, hasPrev, next, hasNext,
+				c.prev, c.hasPrev, c.next, c.hasNext,
+			)
+		}
+	}
+}
+
+func TestAdvance(t *testing.T) {
+	bi := newChain()
+	cases := []struct {
+		start string
+		steps int
+		want  string
+		ok    bool
+	}{
+		{"a", 0, "a", true},
+		{"a", 2, "c", true},
+		{"a", 3, "d", true},
+		{"a", 4, "", false},
+		{"d", -3, "a", true},
+		{"c", -1, "b", true},
+		{"b", -2, "", false},
+		{"z", 1, "", false},
+		{"d", math.MinInt, "", false},
+	}
+	for _, c := range cases {
+		got, ok := Advance(bi, c.start, c.steps)
+		if got != c.want || ok != c.ok {
+			t.Errorf("Advance(bi, %q, %d): got %q, %t; want %q, %t", c.start, c.steps, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestIsInternalNode(t *testing.T) {
+	bi := newChain()
+	cases := []struct {
+		x    string
+		want bool
+	}{
+		{"a", false},
+		{"b", true},
+		{"c", true},
+		{"d", false},
+		{"z", false},
+	}
+	for _, c := range cases {
+		if got := IsInternalNode(bi, c.x); got != c.want {
+			t.Errorf("IsInternalNode(bi, %q) = %t; want %t", c.x, got, c.want)
+		}
+	}
+}
+
+func TestLongestChain(t *testing.T) {
+	cycle := New[int, int]() // 0 -> 1 -> 2 -> 3 -> 4 -> 0
+	for i := 0; i < 5; i++ {
+		cycle.Store(i, (i+1)%5)
+	}
+	mix := New[int, int]()
+	mix.Store(0, 1) // 0 -> 1 -> 0
+	mix.Store(1, 0)
+	mix.Store(2, 2)   // 2 -> 2
+	mix.Store(10, 11) // 10 -> 11 -> 12 -> 13
+	mix.Store(11, 12)
+	mix.Store(12, 13)
+	cases := []struct {
+		desc string
+		bi   *Bimap[int, int]
+		want int
+	}{
+		{desc: "empty", bi: New[int, int](), want: 0},
+		{desc: "single chain", bi: MustFromMap(map[int]int{1: 2, 2: 3, 3: 4}), want: 3},
+		{desc: "cycle", bi: cycle, want: 5},
+		{desc: "fixed point", bi: MustFromMap(map[int]int{7: 7}), want: 1},
+		{desc: "mix", bi: mix, want: 3},
+	}
+	for _, c := range cases {
+		if got := LongestChain(c.bi); got != c.want {
+			t.Errorf("%s: got %d; want %d", c.desc, got, c.want)
+		}
+	}
+}
+
+func TestAdvanceSafe(t *testing.T) {
+	cycle := New[int, int]() // 0 -> 1 -> 2 -> 0
+	cycle.Store(0, 1)
+	cycle.Store(1, 2)
+	cycle.Store(2, 0)
+	cases := []struct {
+		desc  string
+		start int
+		steps int
+		want  int
+		ok    bool
+	}{
+		{desc: "no step", start: 1, steps: 0, want: 1, ok: true},
+		{desc: "within the first turn", start: 0, steps: 2, want: 2, ok: true},
+		{desc: "exactly one turn", start: 0, steps: 3, want: 0, ok: true},
+		{desc: "many turns", start: 1, steps: math.MaxInt, want: 2, ok: true}, // MaxInt%3 == 1,
+		{desc: "many turns backwards", start: 0, steps: -1_000_000_000_001, want: 1, ok: true},
+		{desc: "most turns backwards", start: 0, steps: math.MinInt, want: 1, ok: true}, // 2^63%3 == 2
+		{desc: "absent start", start: 3, steps: math.MinInt, want: 0, ok: false},
+	}
+	for _, c := range cases {
+		got, ok := AdvanceSafe(cycle, c.start, c.steps)
+		if got != c.want || ok != c.ok {
+			t.Errorf("%s: got %d, %t; want %d, %t", c.desc, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestThatAdvanceSafeStopsAtADeadEnd(t *testing.T) {
+	bi := newChain()
+	if got, ok := AdvanceSafe(bi, "a", 3); got != "d" || !ok {
+		t.Errorf("got %q, %t; want %q, %t", got, ok, "d", true)
+	}
+	if got, ok := AdvanceSafe(bi, "a", math.MaxInt); got != "" || ok {
+		t.Errorf("got %q, %t; want %q, %t", got, ok, "", false)
+	}
+	if got, ok := AdvanceSafe(bi, "c", -3); got != "" || ok {
+		t.Errorf("got %q, %t; want %q, %t", got, ok, "", false)
+	}
+	if got, ok := AdvanceSafe(bi, "d", math.MinInt); got != "" || ok {
+		t.Errorf("got %q, %t; want %q, %t", got, ok, "", false)
+	}
+}
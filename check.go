@@ -0,0 +1,103 @@
+// Copyright 2020 Julien Cretel (jub0bs). All rights reserved.
+// Use of this source code is governed by a BSD 3-clause
+// license that can be found in the LICENSE file.
+
+package bimap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CheckRoundTrip reports whether, for every key k of bi,
+// bi.LoadKey(bi.LoadValue(k)) yields k and, for every value v of
+// bi, bi.LoadValue(bi.LoadKey(v)) yields v. A Bimap that is only
+// ever manipulated through its methods always passes this check.
+func CheckRoundTrip[K, V comparable](bi *Bimap[K, V]) bool {
+	for k, v := range bi.fwd().Range {
+		if k2, ok := bi.LoadKey(v); !ok || k2 != k {
+			return false
+		}
+	}
+	for v, k := range bi.inv().Range {
+		if v2, ok := bi.LoadValue(k); !ok || v2 != v {
+			return false
+		}
+	}
+	return true
+}
+
+// DanglingKeys returns the keys of bi whose value does not map back
+// to them, in unspecified order. A Bimap that is only ever
+// manipulated through its methods never has dangling keys; see also
+// CheckRoundTrip and Bimap.Repair.
+func DanglingKeys[K, V comparable](bi *Bimap[K, V]) []K {
+	var dangling []K
+	for k, v := range bi.fwd().Range {
+		if k2, ok := bi.inv().Load(v); !ok || k2 != k {
+			dangling = append(dangling, k)
+		}
+	}
+	return dangling
+}
+
+// AssertEqual reports whether bi contains exactly the key-value
+// pairs of want. If not, details describes, one per line and in
+// lexicographical order, the pairs of want that are missing from
+// bi, the pairs of bi that are absent from want, and the keys that
+// bi and want associate with different values.
+func AssertEqual[K, V comparable](bi *Bimap[K, V], want map[K]V) (ok bool, details string) {
+	var lines []string
+	for k, w := range want {
+		switch v, exists := bi.LoadValue(k); {
+		case !exists:
+			lines = append(lines, fmt.Sprintf("missing: %v:%v", k, w))
+		case v != w:
+			lines = append(lines, fmt.Sprintf("differing: %v: got %v, want %v", k, v, w))
+		}
+	}
+	for k, v := range bi.fwd().Range {
+		if _, exists := want[k]; !exists {
+			lines = append(lines, fmt.Sprintf("extra: %v:%v", k, v))
+		}
+	}
+	sort.Strings(lines)
+	return len(lines) == 0, strings.Join(lines, "\n")
+}
+
+// IsTotalOver returns the keys among allKeys that are absent from
+// bi, in the order in which they appear in allKeys. An empty result
+// means that bi is total over allKeys.
+func IsTotalOver[K, V comparable](bi *Bimap[K, V], allKeys []K) (missing []K) {
+	for _, k := range allKeys {
+		if _, exists := bi.LoadValue(k); !exists {
+			missing = append(missing, k)
+		}
+	}
+	return missing
+}
+
+// ValidateKeysSubset returns the keys of bi that are absent from
+// allowed, in unspecified order. An empty result means that the keys
+// of bi form a subset of allowed.
+func ValidateKeysSubset[K, V comparable](bi *Bimap[K, V], allowed map[K]struct{}) (invalid []K) {
+	for k := range bi.fwd().Range {
+		if !contains(allowed, k) {
+			invalid = append(invalid, k)
+		}
+	}
+	return invalid
+}
+
+// Coverage returns the fraction, between 0 and 1, of the elements
+// of domain that are keys of bi. Duplicate elements of domain count
+// as many times as they occur. If domain is empty, Coverage returns
+// 1, since bi is then vacuously total over domain (see IsTotalOver).
+func Coverage[K, V comparable](bi *Bimap[K, V], domain []K) float64 {
+	if len(domain) == 0 {
+		return 1
+	}
+	missing := IsTotalOver(bi, domain)
+	return float64(len(domain)-len(missing)) / float64(len(domain))
+}
@@ -0,0 +1,153 @@
+package bimap
+
+import (
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestCheckRoundTripOnAValidBimap(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	bi.Store(2, "three")
+	bi.DeleteByKey(1)
+	if !CheckRoundTrip(bi) {
+		t.Error("got false; want true")
+	}
+}
+
+func TestCheckRoundTripDetectsInconsistencies(t *testing.T) {
+	cases := []struct {
+		desc    string
+		forward builtinMap[int, string]
+		inverse builtinMap[string, int]
+	}{
+		{
+			desc:    "missing inverse entry",
+			forward: builtinMap[int, string]{1: "one", 2: "two"},
+			inverse: builtinMap[string, int]{"one": 1},
+		}, {
+			desc:    "missing forward entry",
+			forward: builtinMap[int, string]{1: "one"},
+			inverse: builtinMap[string, int]{"one": 1, "two": 2},
+		}, {
+			desc:    "mismatched entries",
+			forward: builtinMap[int, string]{1: "one", 2: "two"},
+			inverse: builtinMap[string, int]{"one": 2, "two": 1},
+		},
+	}
+	for _, c := range cases {
+		bi := &Bimap[int, string]{forward: c.forward, inverse: c.inverse}
+		if CheckRoundTrip(bi) {
+			t.Errorf("%s: got true; want false", c.desc)
+		}
+	}
+}
+
+func TestDanglingKeys(t *testing.T) {
+	healthy := New[int, string]()
+	healthy.Store(1, "one")
+	healthy.Store(2, "two")
+	if got := DanglingKeys(healthy); len(got) != 0 {
+		t.Errorf("healthy bimap: got %v; want none", got)
+	}
+	corrupted := &Bimap[int, string]{
+		forward: builtinMap[int, string]{1: "one", 2: "two", 3: "three"},
+		inverse: builtinMap[string, int]{"one": 1, "two": 3},
+	}
+	got := DanglingKeys(corrupted)
+	slices.Sort(got)
+	if want := []int{2, 3}; !slices.Equal(got, want) {
+		t.Errorf("corrupted bimap: got %v; want %v", got, want)
+	}
+}
+
+func TestAssertEqualWithMatchingContents(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	ok, details := AssertEqual(bi, map[int]string{1: "one", 2: "two"})
+	if !ok || details != "" {
+		t.Errorf("got %t, %q; want %t, %q", ok, details, true, "")
+	}
+}
+
+func TestAssertEqualDescribesMismatches(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	bi.Store(3, "three")
+	want := map[int]string{1: "one", 2: "deux", 4: "four"}
+	ok, details := AssertEqual(bi, want)
+	const wantDetails = "differing: 2: got two, want deux\n" +
+		"extra: 3:three\n" +
+		"missing: 4:four"
+	if ok || details != wantDetails {
+		t.Errorf("got %t, %q; want %t, %q", ok, details, false, wantDetails)
+	}
+}
+
+func TestIsTotalOver(t *testing.T) {
+	type color int
+	const (
+		red color = iota
+		green
+		blue
+	)
+	allColors := []color{red, green, blue}
+	complete := New[color, string]()
+	complete.Store(red, "red")
+	complete.Store(green, "green")
+	complete.Store(blue, "blue")
+	if missing := IsTotalOver(complete, allColors); len(missing) != 0 {
+		t.Errorf("complete table: got %v; want none", missing)
+	}
+	incomplete := New[color, string]()
+	incomplete.Store(green, "green")
+	missing := IsTotalOver(incomplete, allColors)
+	if want := []color{red, blue}; !slices.Equal(missing, want) {
+		t.Errorf("incomplete table: got %v; want %v", missing, want)
+	}
+}
+
+func TestValidateKeysSubset(t *testing.T) {
+	allowed := map[string]struct{}{"a": {}, "b": {}, "c": {}}
+	valid := New[string, int]()
+	valid.Store("a", 1)
+	valid.Store("c", 3)
+	if invalid := ValidateKeysSubset(valid, allowed); len(invalid) != 0 {
+		t.Errorf("all-valid keys: got %v; want none", invalid)
+	}
+	stale := New[string, int]()
+	stale.Store("a", 1)
+	stale.Store("x", 2)
+	stale.Store("y", 3)
+	invalid := ValidateKeysSubset(stale, allowed)
+	slices.Sort(invalid)
+	if want := []string{"x", "y"}; !slices.Equal(invalid, want) {
+		t.Errorf("some invalid keys: got %v; want %v", invalid, want)
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	bi := New[string, int]()
+	bi.Store("a", 1)
+	bi.Store("b", 2)
+	bi.Store("c", 3)
+	cases := []struct {
+		desc   string
+		domain []string
+		want   float64
+	}{
+		{desc: "full", domain: []string{"a", "b", "c"}, want: 1},
+		{desc: "partial", domain: []string{"a", "b", "x", "y"}, want: 0.5},
+		{desc: "none", domain: []string{"x", "y"}, want: 0},
+		{desc: "empty domain", domain: nil, want: 1},
+	}
+	for _, c := range cases {
+		if got := Coverage(bi, c.domain); got != c.want {
+			t.Errorf("%s: got %v; want %v", c.desc, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2020 Julien Cretel (jub0bs). All rights reserved.
+// Use of this source code is governed by a BSD 3-clause
+// license that can be found in the LICENSE file.
+
+package bimap
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// WriteDOT writes to w a Graphviz DOT representation of bi as a
+// directed graph named name, with an edge from each key to its
+// value. Nodes are identified and labelled by the default format
+// (as produced by the %v verb of package fmt) of keys and values;
+// as a result, keys and values that are formatted identically
+// share a node. Edges are written in lexicographical order.
+func WriteDOT[K, V comparable](w io.Writer, bi *Bimap[K, V], name string) error {
+	edges := make([]string, 0, bi.Size())
+	for k, v := range bi.fwd().Range {
+		from := dotQuote(fmt.Sprint(k))
+		to := dotQuote(fmt.Sprint(v))
+		edges = append(edges, from+" -> "+to)
+	}
+	sort.Strings(edges)
+	if _, err := fmt.Fprintf(w, "digraph %s {\n", dotQuote(name)); err != nil {
+		return err
+	}
+	for _, e := range edges {
+		if _, err := fmt.Fprintf(w, "\t%s;\n", e); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "}\n")
+	return err
+}
+
+var dotEscaper = strings.NewReplacer(`"`, `\"`, `\`, `\\`)
+
+// dotQuote returns s as a double-quoted DOT identifier. Unlike
+// strconv.Quote, it escapes only double quotes and backslashes and
+// leaves every other character, including control characters, as is.
+func dotQuote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
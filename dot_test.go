@@ -0,0 +1,49 @@
+package bimap
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteDOT(t *testing.T) {
+	bi := New[string, int]()
+	bi.Store("b", 2)
+	bi.Store("a", 1)
+	bi.Store(`"quoted"`, 3)
+	var buf bytes.Buffer
+	if err := WriteDOT(&buf, bi, "states"); err != nil {
+		t.Fatalf("got error %v; want nil", err)
+	}
+	const want = `digraph "states" {
+	"\"quoted\"" -> "3";
+	"a" -> "1";
+	"b" -> "2";
+}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestThatWriteDOTEscapesOnlyQuotesAndBackslashes(t *testing.T) {
+	bi := New[string, string]()
+	bi.Store("a\"b\x01", `c\d`)
+	var buf bytes.Buffer
+	if err := WriteDOT(&buf, bi, "tab\tbed"); err != nil {
+		t.Fatalf("got error %v; want nil", err)
+	}
+	const want = "digraph \"tab\tbed\" {\n" +
+		"\t\"a\\\"b\x01\" -> \"c\\\\d\";\n" +
+		"}\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
+
+func TestWriteDOTReportsWriteErrors(t *testing.T) {
+	bi := New[string, int]()
+	bi.Store("a", 1)
+	if err := WriteDOT(failingWriter{}, bi, "g"); err == nil {
+		t.Error("got nil error; want non-nil error")
+	}
+}
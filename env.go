@@ -0,0 +1,33 @@
+// Copyright 2020 Julien Cretel (jub0bs). All rights reserved.
+// Use of this source code is governed by a BSD 3-clause
+// license that can be found in the LICENSE file.
+
+package bimap
+
+import (
+	"os"
+	"strings"
+)
+
+// FromEnv returns a new Bimap that associates the names of the
+// environment variables that start with prefix, stripped of that
+// prefix, with their values. Variables are considered in the order
+// of os.Environ; a variable whose value is already associated with
+// a previous variable is skipped, and its name (including prefix)
+// is reported in conflicts.
+func FromEnv(prefix string) (bi *Bimap[string, string], conflicts []string) {
+	bi = New[string, string]()
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		key, ok := strings.CutPrefix(name, prefix)
+		if !ok {
+			continue
+		}
+		if _, exists := bi.LoadKey(value); exists {
+			conflicts = append(conflicts, name)
+			continue
+		}
+		bi.Store(key, value)
+	}
+	return bi, conflicts
+}
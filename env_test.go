@@ -0,0 +1,33 @@
+package bimap
+
+import "testing"
+
+func TestFromEnv(t *testing.T) {
+	const prefix = "BIMAP_TEST_"
+	t.Setenv(prefix+"ONE", "1")
+	t.Setenv(prefix+"TWO", "2")
+	t.Setenv(prefix+"UNO", "1")
+	t.Setenv("OTHER_BIMAP_TEST_THREE", "3")
+	bi, conflicts := FromEnv(prefix)
+	if size := bi.Size(); size != 2 {
+		t.Errorf("bi.Size() = %d; want %d", size, 2)
+	}
+	if v, exists := bi.LoadValue("TWO"); !exists || v != "2" {
+		t.Errorf("got %q, %t; want %q, %t", v, exists, "2", true)
+	}
+	if _, exists := bi.LoadValue("THREE"); exists {
+		t.Errorf("unexpected key %q", "THREE")
+	}
+	// Which of ONE and UNO wins depends on the order of os.Environ.
+	k, exists := bi.LoadKey("1")
+	if !exists || (k != "ONE" && k != "UNO") {
+		t.Fatalf("got %q, %t; want %q or %q, %t", k, exists, "ONE", "UNO", true)
+	}
+	loser := prefix + "UNO"
+	if k == "UNO" {
+		loser = prefix + "ONE"
+	}
+	if len(conflicts) != 1 || conflicts[0] != loser {
+		t.Errorf("got conflicts %v; want [%s]", conflicts, loser)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2020 Julien Cretel (jub0bs). All rights reserved.
+// Use of this source code is governed by a BSD 3-clause
+// license that can be found in the LICENSE file.
+
+package bimap
+
+// A FrozenBimap is an immutable snapshot of a Bimap.
+// Because it exposes no mutating methods, it is safe for
+// concurrent use by multiple goroutines.
+type FrozenBimap[K, V comparable] struct {
+	bi Bimap[K, V]
+}
+
+// Freeze returns an immutable snapshot of the Bimap's current
+// contents. Subsequent changes to the Bimap are not reflected in
+// the snapshot. The snapshot always stores its pairs in builtin
+// maps, regardless of the Bimap's BackingFactory, because other
+// backings may not tolerate concurrent loads.
+func (bi *Bimap[K, V]) Freeze() *FrozenBimap[K, V] {
+	forward := make(builtinMap[K, V], bi.Size())
+	inverse := make(builtinMap[V, K], bi.Size())
+	for k, v := range bi.fwd().Range {
+		forward[k] = v
+		inverse[v] = k
+	}
+	var fb FrozenBimap[K, V]
+	fb.bi.forward, fb.bi.inverse = forward, inverse
+	return &fb
+}
+
+// LoadValue returns the value stored in the FrozenBimap for a key,
+// or the zero value of the V type if no value is present.
+// The ok result indicates whether the key was found in the map.
+func (fb *FrozenBimap[K, V]) LoadValue(k K) (V, bool) {
+	return fb.bi.LoadValue(k)
+}
+
+// LoadKey returns the key stored in the FrozenBimap for a value,
+// or the zero value of the K type if no key is present.
+// The ok result indicates whether the value was found in the map.
+func (fb *FrozenBimap[K, V]) LoadKey(v V) (K, bool) {
+	return fb.bi.LoadKey(v)
+}
+
+// Size returns the number of key-value pairs in the FrozenBimap.
+func (fb *FrozenBimap[K, V]) Size() int {
+	return fb.bi.Size()
+}
+
+// Range calls f sequentially for each key-value pair present in
+// the FrozenBimap. If f returns false, Range stops the iteration.
+func (fb *FrozenBimap[K, V]) Range(f func(K, V) bool) {
+	fb.bi.fwd().Range(f)
+}
+
+// Keys returns a slice of the keys in the FrozenBimap.
+func (fb *FrozenBimap[K, V]) Keys() []K {
+	return fb.bi.Keys()
+}
+
+// Values returns a slice of the values in the FrozenBimap.
+func (fb *FrozenBimap[K, V]) Values() []V {
+	return fb.bi.Values()
+}
+
+// String returns a string representing the FrozenBimap, in the
+// same format as Bimap.String.
+func (fb *FrozenBimap[K, V]) String() string {
+	return fb.bi.String()
+}
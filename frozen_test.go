@@ -0,0 +1,96 @@
+package bimap
+
+import (
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+type reader[K, V comparable] interface {
+	LoadValue(K) (V, bool)
+	LoadKey(V) (K, bool)
+	Size() int
+	Range(func(K, V) bool)
+	Keys() []K
+	Values() []V
+}
+
+var _ reader[int, string] = (*FrozenBimap[int, string])(nil)
+
+func TestThatAFrozenBimapSupportsReads(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	fb := bi.Freeze()
+	if size := fb.Size(); size != 2 {
+		t.Errorf("fb.Size() = %d; want %d", size, 2)
+	}
+	if v, exists := fb.LoadValue(1); !exists || v != "one" {
+		t.Errorf("got %q, %t; want %q, %t", v, exists, "one", true)
+	}
+	if k, exists := fb.LoadKey("two"); !exists || k != 2 {
+		t.Errorf("got %d, %t; want %d, %t", k, exists, 2, true)
+	}
+	keys := fb.Keys()
+	sort.Ints(keys)
+	if want := []int{1, 2}; !slices.Equal(keys, want) {
+		t.Errorf("got %v; want %v", keys, want)
+	}
+	values := fb.Values()
+	sort.Strings(values)
+	if want := []string{"one", "two"}; !slices.Equal(values, want) {
+		t.Errorf("got %v; want %v", values, want)
+	}
+	var n int
+	fb.Range(func(k int, v string) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Errorf("Range called f %d times after f returned false; want %d", n, 1)
+	}
+}
+
+func TestThatAFrozenBimapIgnoresLaterChanges(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	fb := bi.Freeze()
+	bi.Store(1, "uno")
+	bi.Store(2, "two")
+	if v, exists := fb.LoadValue(1); !exists || v != "one" {
+		t.Errorf("got %q, %t; want %q, %t", v, exists, "one", true)
+	}
+	if size := fb.Size(); size != 1 {
+		t.Errorf("fb.Size() = %d; want %d", size, 1)
+	}
+}
+
+func TestThatFreezeIgnoresTheBackingFactory(t *testing.T) {
+	factory := new(mockFactory[int, string])
+	bi := NewWithBacking[int, string](factory)
+	bi.Store(1, "one")
+	fb := bi.Freeze()
+	if _, ok := fb.bi.forward.(builtinMap[int, string]); !ok {
+		t.Errorf("got forward backing of type %T; want builtinMap", fb.bi.forward)
+	}
+	if _, ok := fb.bi.inverse.(builtinMap[string, int]); !ok {
+		t.Errorf("got inverse backing of type %T; want builtinMap", fb.bi.inverse)
+	}
+	if v, exists := fb.LoadValue(1); !exists || v != "one" {
+		t.Errorf("got %q, %t; want %q, %t", v, exists, "one", true)
+	}
+}
+
+func TestThatAFrozenBimapExposesNoMutators(t *testing.T) {
+	var fb any = new(FrozenBimap[int, string])
+	if _, ok := fb.(interface{ Store(int, string) bool }); ok {
+		t.Error("FrozenBimap unexpectedly has a Store method")
+	}
+	if _, ok := fb.(interface{ DeleteByKey(int) }); ok {
+		t.Error("FrozenBimap unexpectedly has a DeleteByKey method")
+	}
+	if _, ok := fb.(interface{ DeleteByValue(string) }); ok {
+		t.Error("FrozenBimap unexpectedly has a DeleteByValue method")
+	}
+}
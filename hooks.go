@@ -0,0 +1,68 @@
+// Copyright 2020 Julien Cretel (jub0bs). All rights reserved.
+// Use of this source code is governed by a BSD 3-clause
+// license that can be found in the LICENSE file.
+
+package bimap
+
+// A ChangeKind is the kind of a ChangeEvent.
+type ChangeKind int
+
+const (
+	// Stored indicates that a key-value pair was stored.
+	Stored ChangeKind = iota
+	// Deleted indicates that a key-value pair was deleted.
+	Deleted
+)
+
+// A ChangeEvent describes a change to a Bimap.
+type ChangeEvent[K, V comparable] struct {
+	Kind ChangeKind
+	// Pair is the key-value pair that was stored or deleted.
+	Pair Pair[K, V]
+	// Evicted holds the pre-existing key-value pairs (at most two)
+	// that storing Pair removed from the Bimap.
+	Evicted []Pair[K, V]
+}
+
+// OnChange registers a hook to be called after each call to Store
+// that changes the Bimap and after each call to DeleteByKey or
+// DeleteByValue that removes a pair from the Bimap; methods built
+// on those (e.g. ApplyDiff) trigger hooks in the same way. Methods
+// that change several pairs at once (Retain, Drain, RotateValues,
+// TransformValues, TransformKeys, UnmarshalBinary, and
+// UnmarshalJSONWithKeyParser) trigger a Deleted event for each pair
+// they remove, followed by a Stored event for each pair they add.
+// Only Repair, which removes entries that do not form pairs, does
+// not trigger hooks. Hooks run synchronously, in the order in
+// which they were registered, within the call that triggered them;
+// they must not modify the Bimap. OnChange panics if bi is nil.
+func (bi *Bimap[K, V]) OnChange(hook func(event ChangeEvent[K, V])) {
+	bi.mustBeNonNil("OnChange")
+	bi.hooks = append(bi.hooks, hook)
+}
+
+func (bi *Bimap[K, V]) notify(event ChangeEvent[K, V]) {
+	for _, hook := range bi.hooks {
+		hook(event)
+	}
+}
+
+// notifyReplaced notifies the hooks of the differences between old,
+// the Bimap's former forward backing, and its current one: a Deleted
+// event for each pair that disappeared, followed by a Stored event
+// for each pair that appeared.
+func (bi *Bimap[K, V]) notifyReplaced(old Backing[K, V]) {
+	if len(bi.hooks) == 0 {
+		return
+	}
+	for k, v := range old.Range {
+		if !bi.ContainsPair(k, v) {
+			bi.notify(ChangeEvent[K, V]{Kind: Deleted, Pair: Pair[K, V]{k, v}})
+		}
+	}
+	for k, v := range bi.fwd().Range {
+		if v2, ok := old.Load(k); !ok || v2 != v {
+			bi.notify(ChangeEvent[K, V]{Kind: Stored, Pair: Pair[K, V]{k, v}})
+		}
+	}
+}
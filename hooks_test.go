@@ -0,0 +1,132 @@
+package bimap
+
+import (
+	"strconv"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestThatHooksObserveChanges(t *testing.T) {
+	bi := New[int, string]()
+	var events []ChangeEvent[int, string]
+	bi.OnChange(func(e ChangeEvent[int, string]) {
+		events = append(events, e)
+	})
+	var n int
+	bi.OnChange(func(ChangeEvent[int, string]) { n++ })
+
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	bi.Store(1, "one") // no-op
+	bi.Store(1, "two") // evicts 1:"one" and 2:"two"
+	bi.DeleteByKey(3)  // no-op
+	bi.DeleteByKey(1)
+	bi.Store(3, "three")
+	bi.DeleteByValue("three")
+
+	want := []ChangeEvent[int, string]{
+		{Kind: Stored, Pair: Pair[int, string]{1, "one"}},
+		{Kind: Stored, Pair: Pair[int, string]{2, "two"}},
+		{
+			Kind:    Stored,
+			Pair:    Pair[int, string]{1, "two"},
+			Evicted: []Pair[int, string]{{1, "one"}, {2, "two"}},
+		},
+		{Kind: Deleted, Pair: Pair[int, string]{1, "two"}},
+		{Kind: Stored, Pair: Pair[int, string]{3, "three"}},
+		{Kind: Deleted, Pair: Pair[int, string]{3, "three"}},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events; want %d", len(events), len(want))
+	}
+	for i, e := range events {
+		w := want[i]
+		if e.Kind != w.Kind || e.Pair != w.Pair || !slices.Equal(e.Evicted, w.Evicted) {
+			t.Errorf("event %d: got %+v; want %+v", i, e, w)
+		}
+	}
+	if n != len(want) {
+		t.Errorf("second hook called %d times; want %d", n, len(want))
+	}
+}
+
+func TestThatHooksObserveBulkChanges(t *testing.T) {
+	cases := []struct {
+		desc string
+		op   func(bi *Bimap[uint8, uint16])
+		n    int // number of expected events
+	}{
+		{
+			desc: "Retain",
+			op: func(bi *Bimap[uint8, uint16]) {
+				bi.Retain(func(k uint8, _ uint16) bool { return k != 2 })
+			},
+			n: 1,
+		}, {
+			desc: "Drain",
+			op:   func(bi *Bimap[uint8, uint16]) { bi.Drain() },
+			n:    3,
+		}, {
+			desc: "RotateValues",
+			op:   func(bi *Bimap[uint8, uint16]) { bi.RotateValues(1, 2, 3) },
+			n:    6,
+		}, {
+			desc: "RotateValues with a single key",
+			op:   func(bi *Bimap[uint8, uint16]) { bi.RotateValues(1) },
+			n:    0,
+		}, {
+			desc: "TransformValues",
+			op: func(bi *Bimap[uint8, uint16]) {
+				bi.TransformValues(func(v uint16) uint16 { return v % 30 })
+			},
+			n: 2, // only 30 changes, to 0
+		}, {
+			desc: "TransformKeys",
+			op: func(bi *Bimap[uint8, uint16]) {
+				bi.TransformKeys(func(k uint8) uint8 { return k + 1 })
+			},
+			n: 6, // every pair is replaced
+		}, {
+			desc: "UnmarshalBinary",
+			op: func(bi *Bimap[uint8, uint16]) {
+				other := MustFromMap(map[uint8]uint16{1: 10, 4: 40})
+				data, _ := other.MarshalBinary()
+				bi.UnmarshalBinary(data)
+			},
+			n: 3,
+		}, {
+			desc: "UnmarshalJSONWithKeyParser",
+			op: func(bi *Bimap[uint8, uint16]) {
+				parse := func(s string) (uint8, error) {
+					n, err := strconv.ParseUint(s, 10, 8)
+					return uint8(n), err
+				}
+				bi.UnmarshalJSONWithKeyParser([]byte(`{"1":10,"2":21}`), parse)
+			},
+			n: 3,
+		},
+	}
+	for _, c := range cases {
+		bi := MustFromMap(map[uint8]uint16{1: 10, 2: 20, 3: 30})
+		// mirror replicates bi's contents from the events alone.
+		mirror := map[uint8]uint16{1: 10, 2: 20, 3: 30}
+		var n int
+		bi.OnChange(func(e ChangeEvent[uint8, uint16]) {
+			n++
+			switch e.Kind {
+			case Deleted:
+				delete(mirror, e.Pair.Key)
+			case Stored:
+				mirror[e.Pair.Key] = e.Pair.Value
+			}
+		})
+		c.op(bi)
+		if n != c.n {
+			t.Errorf("%s: got %d events; want %d", c.desc, n, c.n)
+		}
+		if ok, details := AssertEqual(bi, mirror); !ok {
+			t.Errorf("%s: mirror out of sync:\n%s", c.desc, details)
+		}
+	}
+}
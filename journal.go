@@ -0,0 +1,45 @@
+// Copyright 2020 Julien Cretel (jub0bs). All rights reserved.
+// Use of this source code is governed by a BSD 3-clause
+// license that can be found in the LICENSE file.
+
+package bimap
+
+// EnableJournal makes the Bimap record, in order, the key-value
+// pairs of subsequent successful calls to Store; see Journal.
+// Calls to Store made before EnableJournal are not recorded, and
+// calling EnableJournal again has no effect.
+// EnableJournal panics if bi is nil.
+func (bi *Bimap[K, V]) EnableJournal() {
+	bi.mustBeNonNil("EnableJournal")
+	bi.journaling = true
+}
+
+// Journal returns, in order, the key-value pairs of the successful
+// calls to Store recorded since journaling was enabled (see
+// EnableJournal). The result is nil if journaling is disabled.
+func (bi *Bimap[K, V]) Journal() []Pair[K, V] {
+	if bi == nil || !bi.journaling {
+		return nil
+	}
+	j := make([]Pair[K, V], len(bi.journal))
+	copy(j, bi.journal)
+	return j
+}
+
+// Replay returns a new Bimap obtained by storing the pairs of
+// journal in order, e.g. a journal obtained from Bimap.Journal.
+// Journals only record calls to Store (including those made by
+// methods built on it, such as ApplyDiff); the result is therefore
+// equivalent to the journaled Bimap only if that Bimap was empty
+// when journaling was enabled and has not been changed since by
+// any other method, in particular DeleteByKey, DeleteByValue,
+// SoftDeleteByKey, Retain, Drain, RotateValues, TransformValues,
+// TransformKeys, Repair, UnmarshalBinary, or
+// UnmarshalJSONWithKeyParser.
+func Replay[K, V comparable](journal []Pair[K, V]) *Bimap[K, V] {
+	bi := New[K, V]()
+	for _, p := range journal {
+		bi.Store(p.Key, p.Value)
+	}
+	return bi
+}
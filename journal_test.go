@@ -0,0 +1,66 @@
+package bimap
+
+import (
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestJournalAndReplay(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(0, "zero") // not recorded
+	bi.DeleteByKey(0)
+	if j := bi.Journal(); j != nil {
+		t.Errorf("got journal %v before EnableJournal; want nil", j)
+	}
+	bi.EnableJournal()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	bi.Store(1, "two") // evicts 1:"one" and 2:"two"
+	bi.Store(3, "three")
+	bi.Store(4, "three") // evicts 3:"three"
+	bi.Store(5, "")
+	bi.Store(6, "") // evicts 5:""
+
+	want := []Pair[int, string]{
+		{1, "one"},
+		{2, "two"},
+		{1, "two"},
+		{3, "three"},
+		{4, "three"},
+		{5, ""},
+		{6, ""},
+	}
+	journal := bi.Journal()
+	if !slices.Equal(journal, want) {
+		t.Errorf("got journal %v; want %v", journal, want)
+	}
+	if replayed := Replay(journal); !replayed.Equal(bi) {
+		t.Errorf("got %v; want %v", replayed, bi)
+	}
+}
+
+func TestThatJournalOmitsFailedStores(t *testing.T) {
+	bi := NewNonZero[int, string]()
+	bi.EnableJournal()
+	bi.Store(1, "one")
+	bi.Store(0, "zero") // rejected
+	bi.SetMaxSize(1)
+	bi.Store(2, "two") // rejected
+	want := []Pair[int, string]{{1, "one"}}
+	if got := bi.Journal(); !slices.Equal(got, want) {
+		t.Errorf("got journal %v; want %v", got, want)
+	}
+}
+
+func TestThatJournalReturnsACopy(t *testing.T) {
+	bi := New[int, string]()
+	bi.EnableJournal()
+	bi.Store(1, "one")
+	j := bi.Journal()
+	j[0] = Pair[int, string]{2, "two"}
+	want := []Pair[int, string]{{1, "one"}}
+	if got := bi.Journal(); !slices.Equal(got, want) {
+		t.Errorf("got journal %v; want %v", got, want)
+	}
+}
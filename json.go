@@ -0,0 +1,60 @@
+// Copyright 2020 Julien Cretel (jub0bs). All rights reserved.
+// Use of this source code is governed by a BSD 3-clause
+// license that can be found in the LICENSE file.
+
+package bimap
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// UnmarshalJSONWithKeyParser replaces the contents of the Bimap by
+// the members of the JSON object encoded in data, using parseKey
+// to convert the object's names to keys. It returns an error, and
+// leaves the Bimap unchanged, if data isn't a valid JSON object
+// whose members' values can be decoded into V, if parseKey fails,
+// or if the resulting pairs do not form a one-to-one
+// correspondence or contain keys or values that Store would
+// reject. UnmarshalJSONWithKeyParser panics if bi is nil.
+func (bi *Bimap[K, V]) UnmarshalJSONWithKeyParser(data []byte, parseKey func(string) (K, error)) error {
+	bi.mustBeNonNil("UnmarshalJSONWithKeyParser")
+	var m map[string]V
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	forward, inverse := bi.newBackings(len(m))
+	for name, v := range m {
+		k, err := parseKey(name)
+		if err != nil {
+			return fmt.Errorf("bimap: invalid key %q: %w", name, err)
+		}
+		if err := bi.insertUnique(forward, inverse, k, v); err != nil {
+			return err
+		}
+	}
+	old := bi.fwd()
+	bi.setBackings(forward, inverse)
+	bi.notifyReplaced(old)
+	return nil
+}
+
+// WriteJSONL writes the key-value pairs of the Bimap to w in the
+// JSON Lines format, i.e. one JSON object per line, each with a
+// "key" and a "value" member. The order of pairs is unspecified.
+// WriteJSONL returns an error if a key or value cannot be encoded
+// as JSON or if writing to w fails.
+func (bi *Bimap[K, V]) WriteJSONL(w io.Writer) error {
+	type line struct {
+		Key   K `json:"key"`
+		Value V `json:"value"`
+	}
+	enc := json.NewEncoder(w)
+	for k, v := range bi.fwd().Range {
+		if err := enc.Encode(line{k, v}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
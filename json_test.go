@@ -0,0 +1,92 @@
+package bimap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestUnmarshalJSONWithKeyParser(t *testing.T) {
+	data := []byte(`{"1": "one", "2": "two", "10": "ten"}`)
+	bi := New[int, string]()
+	if err := bi.UnmarshalJSONWithKeyParser(data, strconv.Atoi); err != nil {
+		t.Fatalf("got error %v; want nil", err)
+	}
+	want := New[int, string]()
+	want.Store(1, "one")
+	want.Store(2, "two")
+	want.Store(10, "ten")
+	if !bi.Equal(want) {
+		t.Errorf("got %v; want %v", bi, want)
+	}
+}
+
+func TestThatUnmarshalJSONWithKeyParserReportsErrors(t *testing.T) {
+	cases := []struct {
+		desc string
+		data string
+	}{
+		{"invalid JSON", `{"1": "one"`},
+		{"not an object", `["one"]`},
+		{"unparsable key", `{"1": "one", "two": "two"}`},
+		{"duplicate key after parsing", `{"1": "one", "01": "uno"}`},
+		{"duplicate value", `{"1": "one", "2": "one"}`},
+	}
+	for _, c := range cases {
+		bi := New[int, string]()
+		bi.Store(42, "forty-two")
+		if err := bi.UnmarshalJSONWithKeyParser([]byte(c.data), strconv.Atoi); err == nil {
+			t.Errorf("%s: got nil error; want non-nil error", c.desc)
+		}
+		if v, exists := bi.LoadValue(42); bi.Size() != 1 || !exists || v != "forty-two" {
+			t.Errorf("%s: bimap unexpectedly modified: %v", c.desc, bi)
+		}
+	}
+}
+
+func TestThatUnmarshalJSONWithKeyParserWrapsParseErrors(t *testing.T) {
+	errBadKey := errors.New("bad key")
+	parseKey := func(string) (int, error) { return 0, errBadKey }
+	var bi Bimap[int, string]
+	err := bi.UnmarshalJSONWithKeyParser([]byte(`{"1": "one"}`), parseKey)
+	if !errors.Is(err, errBadKey) {
+		t.Errorf("got %v; want an error wrapping %v", err, errBadKey)
+	}
+}
+
+func TestWriteJSONL(t *testing.T) {
+	bi := New[string, int]()
+	bi.Store("one", 1)
+	bi.Store("two", 2)
+	bi.Store("three", 3)
+	var buf bytes.Buffer
+	if err := bi.WriteJSONL(&buf); err != nil {
+		t.Fatalf("got error %v; want nil", err)
+	}
+	got := make(map[string]int)
+	sc := bufio.NewScanner(&buf)
+	for sc.Scan() {
+		var p struct {
+			Key   string `json:"key"`
+			Value int    `json:"value"`
+		}
+		if err := json.Unmarshal(sc.Bytes(), &p); err != nil {
+			t.Fatalf("line %q: got error %v; want nil", sc.Text(), err)
+		}
+		got[p.Key] = p.Value
+	}
+	if ok, details := AssertEqual(bi, got); !ok || len(got) != bi.Size() {
+		t.Errorf("got unexpected lines:\n%s", details)
+	}
+}
+
+func TestThatWriteJSONLReportsWriteErrors(t *testing.T) {
+	bi := New[string, int]()
+	bi.Store("one", 1)
+	if err := bi.WriteJSONL(failingWriter{}); err == nil {
+		t.Error("got nil error; want non-nil error")
+	}
+}
@@ -0,0 +1,305 @@
+// Copyright 2020 Julien Cretel (jub0bs). All rights reserved.
+// Use of this source code is governed by a BSD 3-clause
+// license that can be found in the LICENSE file.
+
+package bimap
+
+import (
+	"container/heap"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"iter"
+	"math/rand"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/exp/constraints"
+	"golang.org/x/exp/slices"
+)
+
+// RangePairs returns an iterator over the key-value pairs of bi
+// whose key k satisfies lo <= k < hi, in ascending order of keys.
+func RangePairs[K constraints.Ordered, V comparable](bi *Bimap[K, V], lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		keys := sortedKeys(bi)
+		i, _ := slices.BinarySearch(keys, lo)
+		for _, k := range keys[i:] {
+			if !(k < hi) {
+				return
+			}
+			v, _ := bi.LoadValue(k)
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// OrderedAllDesc returns an iterator over the key-value pairs of
+// bi in descending order of keys.
+func OrderedAllDesc[K constraints.Ordered, V comparable](bi *Bimap[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		keys := sortedKeys(bi)
+		for i := len(keys) - 1; i >= 0; i-- {
+			k := keys[i]
+			v, _ := bi.LoadValue(k)
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// EnumeratePairs returns the key-value pairs of bi in ascending
+// order of keys, each tagged with its 0-based position in that
+// order.
+func EnumeratePairs[K constraints.Ordered, V comparable](bi *Bimap[K, V]) []struct {
+	Index int
+	Key   K
+	Value V
+} {
+	keys := sortedKeys(bi)
+	pairs := make([]struct {
+		Index int
+		Key   K
+		Value V
+	}, len(keys))
+	for i, k := range keys {
+		pairs[i].Index = i
+		pairs[i].Key = k
+		pairs[i].Value, _ = bi.LoadValue(k)
+	}
+	return pairs
+}
+
+// Fprint writes the key-value pairs of bi to w in ascending order
+// of keys, formatting each pair with fmt.Fprintf(w, format, k, v).
+func Fprint[K constraints.Ordered, V comparable](w io.Writer, bi *Bimap[K, V], format string) error {
+	for _, k := range sortedKeys(bi) {
+		v, _ := bi.LoadValue(k)
+		if _, err := fmt.Fprintf(w, format, k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteAligned writes the key-value pairs of bi to w in ascending
+// order of keys, one "key : value" line per pair, with keys padded
+// on the right so that the colons line up. Keys and values are
+// formatted with their default format (as produced by the %v verb
+// of package fmt), and widths are measured in runes.
+func WriteAligned[K constraints.Ordered, V comparable](w io.Writer, bi *Bimap[K, V]) error {
+	keys := sortedKeys(bi)
+	strs := make([]string, len(keys))
+	var width int
+	for i, k := range keys {
+		strs[i] = fmt.Sprint(k)
+		width = max(width, utf8.RuneCountInString(strs[i]))
+	}
+	for i, k := range keys {
+		v, _ := bi.LoadValue(k)
+		if _, err := fmt.Fprintf(w, "%-*s : %v\n", width, strs[i], v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OrderedString returns a string representing bi, in the same
+// format as bi.String, in which pairs are sorted by key.
+func OrderedString[K constraints.Ordered, V comparable](bi *Bimap[K, V]) string {
+	var b strings.Builder
+	b.WriteString("Bimap[")
+	for i, k := range sortedKeys(bi) {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		v, _ := bi.LoadValue(k)
+		fmt.Fprintf(&b, "%v:%v", k, v)
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// CanonicalBytes returns a byte representation of bi that only
+// depends on bi's contents, not on how bi was built; it is suitable
+// for hashing or signing. The representation consists of, for each
+// pair in ascending order of keys, the key's and the value's
+// default formats (as produced by the %v verb of package fmt),
+// each prefixed by its length as a uvarint; negative zero is
+// formatted as positive zero, to which it is equal.
+//
+// Because it relies on formatting, CanonicalBytes only supports
+// value types whose default format is determined by their equality,
+// such as booleans, numbers, strings, and structs and arrays of
+// those types, except that negative zero is only normalised in
+// values of floating-point or complex type, not within structs or
+// arrays. In particular, values of a type that contains pointers,
+// channels, or interfaces that hold them are formatted by address
+// rather than by contents.
+func CanonicalBytes[K constraints.Ordered, V comparable](bi *Bimap[K, V]) []byte {
+	var buf, tmp []byte
+	for _, k := range sortedKeys(bi) {
+		v, _ := bi.LoadValue(k)
+		tmp = fmt.Appendf(tmp[:0], "%v", positiveZero(k))
+		buf = binary.AppendUvarint(buf, uint64(len(tmp)))
+		buf = append(buf, tmp...)
+		tmp = fmt.Appendf(tmp[:0], "%v", positiveZero(v))
+		buf = binary.AppendUvarint(buf, uint64(len(tmp)))
+		buf = append(buf, tmp...)
+	}
+	return buf
+}
+
+// positiveZero returns t, except that it replaces negative zero by
+// positive zero in values of floating-point or complex type,
+// including the real and imaginary parts of complex numbers.
+func positiveZero[T any](t T) any {
+	v := reflect.ValueOf(&t).Elem()
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(v.Float() + 0) // -0 + 0 == +0
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		v.SetComplex(complex(real(c)+0, imag(c)+0))
+	}
+	return t
+}
+
+// KeysByValue returns the keys of bi sorted in ascending order of
+// their values.
+func KeysByValue[K comparable, V constraints.Ordered](bi *Bimap[K, V]) []K {
+	values := bi.Values()
+	slices.Sort(values)
+	keys := make([]K, len(values))
+	for i, v := range values {
+		keys[i], _ = bi.LoadKey(v)
+	}
+	return keys
+}
+
+// ValuesOrderedByKey returns the values of bi sorted in ascending
+// order of their keys.
+func ValuesOrderedByKey[K constraints.Ordered, V comparable](bi *Bimap[K, V]) []V {
+	keys := sortedKeys(bi)
+	values := make([]V, len(keys))
+	for i, k := range keys {
+		values[i], _ = bi.LoadValue(k)
+	}
+	return values
+}
+
+// TopN returns the (at most) n key-value pairs of bi that have the
+// largest values, sorted in descending order of values.
+// The complexity is O(s log n), where s is the size of bi.
+func TopN[K comparable, V constraints.Ordered](bi *Bimap[K, V], n int) []Pair[K, V] {
+	if n <= 0 {
+		return nil
+	}
+	h := make(minHeap[K, V], 0, min(n, bi.Size()))
+	for k, v := range bi.fwd().Range {
+		if len(h) < n {
+			heap.Push(&h, Pair[K, V]{k, v})
+		} else if h[0].Value < v {
+			h[0] = Pair[K, V]{k, v}
+			heap.Fix(&h, 0)
+		}
+	}
+	pairs := make([]Pair[K, V], len(h))
+	for i := len(pairs) - 1; i >= 0; i-- {
+		pairs[i] = heap.Pop(&h).(Pair[K, V])
+	}
+	return pairs
+}
+
+// minHeap is a min-heap of pairs ordered by value.
+type minHeap[K comparable, V constraints.Ordered] []Pair[K, V]
+
+func (h minHeap[K, V]) Len() int           { return len(h) }
+func (h minHeap[K, V]) Less(i, j int) bool { return h[i].Value < h[j].Value }
+func (h minHeap[K, V]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *minHeap[K, V]) Push(x any) {
+	*h = append(*h, x.(Pair[K, V]))
+}
+
+func (h *minHeap[K, V]) Pop() any {
+	old := *h
+	p := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return p
+}
+
+func sortedKeys[K constraints.Ordered, V comparable](bi *Bimap[K, V]) []K {
+	keys := bi.Keys()
+	slices.Sort(keys)
+	return keys
+}
+
+// ShuffledPairs returns the key-value pairs of bi in a pseudo-random
+// order determined solely by seed and by bi's contents: the same seed
+// always yields the same order. The pairs are first sorted by the
+// default format (as produced by the %v verb of package fmt) of their
+// keys, then shuffled by a source seeded with seed.
+func ShuffledPairs[K, V comparable](bi *Bimap[K, V], seed int64) []Pair[K, V] {
+	pairs := canonicalPairs(bi)
+	r := rand.New(rand.NewSource(seed))
+	r.Shuffle(len(pairs), func(i, j int) {
+		pairs[i], pairs[j] = pairs[j], pairs[i]
+	})
+	return pairs
+}
+
+// WeightedSample returns a key-value pair of bi chosen at random,
+// using r as the source of randomness, with a probability
+// proportional to the weight that weight assigns to it. Negative
+// and NaN weights are treated as zero. The ok result is false if bi
+// is empty or if all weights are zero. WeightedSample visits the
+// pairs in a single pass and in an order that only depends on bi's
+// contents, so that its result is determined by the state of r.
+func WeightedSample[K, V comparable](bi *Bimap[K, V], r *rand.Rand, weight func(K, V) float64) (Pair[K, V], bool) {
+	var (
+		sample Pair[K, V]
+		ok     bool
+		total  float64
+	)
+	for _, p := range canonicalPairs(bi) {
+		w := weight(p.Key, p.Value)
+		if !(w > 0) { // negative, zero, or NaN
+			continue
+		}
+		total += w
+		if r.Float64()*total < w { // replace with probability w/total
+			sample, ok = p, true
+		}
+	}
+	return sample, ok
+}
+
+// canonicalPairs returns the key-value pairs of bi sorted by the
+// default format (as produced by the %v verb of package fmt) of
+// their keys and, for equally formatted keys, of their values.
+func canonicalPairs[K, V comparable](bi *Bimap[K, V]) []Pair[K, V] {
+	type entry struct {
+		pair     Pair[K, V]
+		key, val string
+	}
+	entries := make([]entry, 0, bi.Size())
+	for k, v := range bi.fwd().Range {
+		entries = append(entries, entry{Pair[K, V]{k, v}, fmt.Sprint(k), fmt.Sprint(v)})
+	}
+	slices.SortFunc(entries, func(a, b entry) bool {
+		if a.key != b.key {
+			return a.key < b.key
+		}
+		return a.val < b.val
+	})
+	pairs := make([]Pair[K, V], len(entries))
+	for i, e := range entries {
+		pairs[i] = e.pair
+	}
+	return pairs
+}
@@ -0,0 +1,385 @@
+package bimap
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestRangePairsYieldsPairsInTheHalfOpenIntervalInOrder(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(4, "four")
+	bi.Store(1, "one")
+	bi.Store(3, "three")
+	bi.Store(2, "two")
+	bi.Store(5, "five")
+	var keys []int
+	var values []string
+	for k, v := range RangePairs(bi, 2, 5) {
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	wantKeys := []int{2, 3, 4}
+	wantValues := []string{"two", "three", "four"}
+	if !slices.Equal(keys, wantKeys) || !slices.Equal(values, wantValues) {
+		t.Errorf("got %v, %v; want %v, %v", keys, values, wantKeys, wantValues)
+	}
+}
+
+func TestRangePairsWithEmptyIntervalYieldsNothing(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	for k, v := range RangePairs(bi, 2, 2) {
+		t.Errorf("unexpected pair %d:%q", k, v)
+	}
+}
+
+func TestRangePairsSupportsEarlyTermination(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	bi.Store(3, "three")
+	var keys []int
+	for k := range RangePairs(bi, 0, 10) {
+		keys = append(keys, k)
+		if k == 2 {
+			break
+		}
+	}
+	want := []int{1, 2}
+	if !slices.Equal(keys, want) {
+		t.Errorf("got %v; want %v", keys, want)
+	}
+}
+
+func TestEnumeratePairsAssignsSequentialIndicesInKeyOrder(t *testing.T) {
+	bi := New[string, int]()
+	bi.Store("c", 3)
+	bi.Store("a", 1)
+	bi.Store("b", 2)
+	got := EnumeratePairs(bi)
+	want := []struct {
+		Index int
+		Key   string
+		Value int
+	}{
+		{0, "a", 1},
+		{1, "b", 2},
+		{2, "c", 3},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+}
+
+func TestEnumeratePairsOnEmptyBimap(t *testing.T) {
+	bi := New[string, int]()
+	if got := EnumeratePairs(bi); len(got) != 0 {
+		t.Errorf("got %v; want empty", got)
+	}
+}
+
+func TestFprintWritesFormattedPairsInKeyOrder(t *testing.T) {
+	bi := New[string, int]()
+	bi.Store("b", 2)
+	bi.Store("c", 3)
+	bi.Store("a", 1)
+	var buf bytes.Buffer
+	if err := Fprint(&buf, bi, "| %s | %d |\n"); err != nil {
+		t.Fatalf("got error %v; want nil", err)
+	}
+	got := buf.String()
+	want := "| a | 1 |\n| b | 2 |\n| c | 3 |\n"
+	if got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestFprintReportsWriteErrors(t *testing.T) {
+	bi := New[string, int]()
+	bi.Store("a", 1)
+	if err := Fprint(failingWriter{}, bi, "%s=%d\n"); err == nil {
+		t.Error("got nil error; want non-nil error")
+	}
+}
+
+func TestWriteAligned(t *testing.T) {
+	bi := New[string, int]()
+	bi.Store("b", 2)
+	bi.Store("ccc", 3)
+	bi.Store("äa", 1)
+	var buf bytes.Buffer
+	if err := WriteAligned(&buf, bi); err != nil {
+		t.Fatalf("got error %v; want nil", err)
+	}
+	const want = "b   : 2\n" +
+		"ccc : 3\n" +
+		"äa  : 1\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
+
+func TestOrderedStringIsDeterministic(t *testing.T) {
+	const want = "Bimap[1:one 2:two 3:three 10:ten]"
+	for i := 0; i < 100; i++ {
+		bi := New[int, string]()
+		bi.Store(10, "ten")
+		bi.Store(3, "three")
+		bi.Store(1, "one")
+		bi.Store(2, "two")
+		if got := OrderedString(bi); got != want {
+			t.Fatalf("got %q; want %q", got, want)
+		}
+	}
+}
+
+func TestOrderedStringOfEmptyBimap(t *testing.T) {
+	bi := New[int, string]()
+	const want = "Bimap[]"
+	if got := OrderedString(bi); got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
+
+func TestOrderedAllDescYieldsPairsInDescendingKeyOrder(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(2, "two")
+	bi.Store(3, "three")
+	bi.Store(1, "one")
+	var keys []int
+	var values []string
+	for k, v := range OrderedAllDesc(bi) {
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	wantKeys := []int{3, 2, 1}
+	wantValues := []string{"three", "two", "one"}
+	if !slices.Equal(keys, wantKeys) || !slices.Equal(values, wantValues) {
+		t.Errorf("got %v, %v; want %v, %v", keys, values, wantKeys, wantValues)
+	}
+}
+
+func TestOrderedAllDescSupportsEarlyTermination(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(2, "two")
+	bi.Store(3, "three")
+	bi.Store(1, "one")
+	var keys []int
+	for k := range OrderedAllDesc(bi) {
+		keys = append(keys, k)
+		if k == 2 {
+			break
+		}
+	}
+	want := []int{3, 2}
+	if !slices.Equal(keys, want) {
+		t.Errorf("got %v; want %v", keys, want)
+	}
+}
+
+func TestThatCanonicalBytesIgnoresInsertionOrder(t *testing.T) {
+	x := New[string, int]()
+	x.Store("a", 1)
+	x.Store("b", 2)
+	x.Store("c", 3)
+	y := New[string, int]()
+	y.Store("c", 0)
+	y.Store("b", 2)
+	y.Store("a", 1)
+	y.Store("c", 3)
+	y.Store("d", 4)
+	y.DeleteByKey("d")
+	if got, want := CanonicalBytes(y), CanonicalBytes(x); !bytes.Equal(got, want) {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
+
+func TestThatCanonicalBytesDistinguishesDifferentContents(t *testing.T) {
+	x := New[string, string]()
+	x.Store("ab", "c")
+	y := New[string, string]()
+	y.Store("a", "bc")
+	if got, other := CanonicalBytes(x), CanonicalBytes(y); bytes.Equal(got, other) {
+		t.Errorf("got identical representations %q", got)
+	}
+}
+
+func TestThatCanonicalBytesIgnoresTheSignOfZero(t *testing.T) {
+	negZero := math.Copysign(0, -1)
+	x := New[float64, float64]()
+	x.Store(0, 1)
+	x.Store(2, 0)
+	y := New[float64, float64]()
+	y.Store(negZero, 1)
+	y.Store(2, negZero)
+	if !x.Equal(y) {
+		t.Fatalf("got %v and %v; want equal bimaps", x, y)
+	}
+	if got, want := CanonicalBytes(y), CanonicalBytes(x); !bytes.Equal(got, want) {
+		t.Errorf("got %q; want %q", got, want)
+	}
+	type celsius float32
+	c1 := New[string, celsius]()
+	c1.Store("freezing", celsius(negZero))
+	c2 := New[string, celsius]()
+	c2.Store("freezing", 0)
+	if got, want := CanonicalBytes(c1), CanonicalBytes(c2); !bytes.Equal(got, want) {
+		t.Errorf("named float type: got %q; want %q", got, want)
+	}
+	z1 := New[int8, complex128]()
+	z1.Store(1, complex(negZero, negZero))
+	z2 := New[int8, complex128]()
+	z2.Store(1, 0)
+	if got, want := CanonicalBytes(z1), CanonicalBytes(z2); !bytes.Equal(got, want) {
+		t.Errorf("complex type: got %q; want %q", got, want)
+	}
+}
+
+func TestTopN(t *testing.T) {
+	bi := New[string, int]()
+	bi.Store("alice", 30)
+	bi.Store("bob", 10)
+	bi.Store("carol", 50)
+	bi.Store("dave", 20)
+	bi.Store("eve", 40)
+	all := []Pair[string, int]{
+		{"carol", 50},
+		{"eve", 40},
+		{"alice", 30},
+		{"dave", 20},
+		{"bob", 10},
+	}
+	cases := []struct {
+		n    int
+		want []Pair[string, int]
+	}{
+		{0, nil},
+		{2, all[:2]},
+		{5, all},
+		{7, all},
+	}
+	for _, c := range cases {
+		if got := TopN(bi, c.n); !slices.Equal(got, c.want) {
+			t.Errorf("TopN(bi, %d) = %v; want %v", c.n, got, c.want)
+		}
+	}
+}
+
+func TestKeysByValue(t *testing.T) {
+	bi := New[string, int]()
+	bi.Store("c", 1)
+	bi.Store("a", 3)
+	bi.Store("b", 2)
+	bi.Store("d", -1)
+	got := KeysByValue(bi)
+	want := []string{"d", "c", "b", "a"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+}
+
+func TestValuesOrderedByKey(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(3, "a")
+	bi.Store(1, "c")
+	bi.Store(2, "d")
+	bi.Store(-1, "b")
+	got := ValuesOrderedByKey(bi)
+	want := []string{"b", "c", "d", "a"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+}
+
+func TestShuffledPairsIsDeterministic(t *testing.T) {
+	build := func(order []int) *Bimap[int, int] {
+		bi := New[int, int]()
+		for _, i := range order {
+			bi.Store(i, i*10)
+		}
+		return bi
+	}
+	bi1 := build([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+	bi2 := build([]int{9, 8, 7, 6, 5, 4, 3, 2, 1, 0})
+	got := ShuffledPairs(bi1, 42)
+	if len(got) != bi1.Size() {
+		t.Fatalf("got %d pairs; want %d", len(got), bi1.Size())
+	}
+	for _, p := range got {
+		if !bi1.ContainsPair(p.Key, p.Value) {
+			t.Errorf("unexpected pair %v", p)
+		}
+	}
+	for i := 0; i < 5; i++ {
+		if again := ShuffledPairs(bi1, 42); !slices.Equal(again, got) {
+			t.Errorf("got %v; want %v", again, got)
+		}
+	}
+	if other := ShuffledPairs(bi2, 42); !slices.Equal(other, got) {
+		t.Errorf("got %v; want %v", other, got)
+	}
+}
+
+func TestShuffledPairsDependsOnSeed(t *testing.T) {
+	bi := New[int, int]()
+	for i := 0; i < 20; i++ {
+		bi.Store(i, i*10)
+	}
+	if a, b := ShuffledPairs(bi, 1), ShuffledPairs(bi, 2); slices.Equal(a, b) {
+		t.Errorf("got identical orders %v for different seeds", a)
+	}
+}
+
+func TestWeightedSampleIsDeterministic(t *testing.T) {
+	build := func(order []string) *Bimap[string, int] {
+		bi := New[string, int]()
+		for _, k := range order {
+			bi.Store(k, len(k))
+		}
+		return bi
+	}
+	bi1 := build([]string{"a", "bb", "ccc", "dddd"})
+	bi2 := build([]string{"dddd", "ccc", "bb", "a"})
+	weight := func(k string, _ int) float64 { return float64(len(k)) }
+	for seed := int64(0); seed < 10; seed++ {
+		p1, ok1 := WeightedSample(bi1, rand.New(rand.NewSource(seed)), weight)
+		p2, ok2 := WeightedSample(bi2, rand.New(rand.NewSource(seed)), weight)
+		if !ok1 || !ok2 || p1 != p2 {
+			t.Errorf("seed %d: got %v, %t and %v, %t; want identical samples", seed, p1, ok1, p2, ok2)
+		}
+	}
+}
+
+func TestThatWeightedSampleIgnoresNonPositiveWeights(t *testing.T) {
+	bi := New[string, int]()
+	bi.Store("neg", -1)
+	bi.Store("zero", 0)
+	bi.Store("pos", 1)
+	weight := func(_ string, v int) float64 { return float64(v) }
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		p, ok := WeightedSample(bi, r, weight)
+		if want := (Pair[string, int]{"pos", 1}); !ok || p != want {
+			t.Fatalf("got %v, %t; want %v, %t", p, ok, want, true)
+		}
+	}
+	bi.DeleteByKey("pos")
+	if p, ok := WeightedSample(bi, r, weight); ok {
+		t.Errorf("all weights non-positive: got %v, %t; want %t", p, ok, false)
+	}
+	if p, ok := WeightedSample(New[string, int](), r, weight); ok {
+		t.Errorf("empty bimap: got %v, %t; want %t", p, ok, false)
+	}
+}
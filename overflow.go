@@ -0,0 +1,32 @@
+// Copyright 2020 Julien Cretel (jub0bs). All rights reserved.
+// Use of this source code is governed by a BSD 3-clause
+// license that can be found in the LICENSE file.
+
+package bimap
+
+// StoreWithOverflow behaves like Store, except when the value is
+// already associated with another key: rather than evicting the
+// pre-existing pair, StoreWithOverflow leaves the Bimap unchanged
+// and appends the given key-value pair to the Bimap's overflow (see
+// Overflow). It returns whether or not the pair was stored.
+// StoreWithOverflow panics if bi is nil.
+func (bi *Bimap[K, V]) StoreWithOverflow(key K, value V) bool {
+	bi.mustBeNonNil("StoreWithOverflow")
+	if k, exists := bi.inv().Load(value); exists && k != key {
+		bi.spilled = append(bi.spilled, Pair[K, V]{key, value})
+		return false
+	}
+	return bi.Store(key, value)
+}
+
+// Overflow returns, in order, the key-value pairs that calls to
+// StoreWithOverflow diverted from the Bimap because of a value
+// collision.
+func (bi *Bimap[K, V]) Overflow() []Pair[K, V] {
+	if bi == nil || len(bi.spilled) == 0 {
+		return nil
+	}
+	pairs := make([]Pair[K, V], len(bi.spilled))
+	copy(pairs, bi.spilled)
+	return pairs
+}
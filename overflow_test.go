@@ -0,0 +1,46 @@
+package bimap
+
+import (
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestStoreWithOverflow(t *testing.T) {
+	bi := New[string, int]()
+	cases := []struct {
+		key   string
+		value int
+		want  bool
+	}{
+		{"a", 1, true},
+		{"b", 2, true},
+		{"c", 1, false}, // collides with a:1
+		{"a", 1, true},  // already present
+		{"a", 3, true},  // replaces a:1
+		{"d", 2, false}, // collides with b:2
+		{"e", 1, true},  // 1 is free again
+	}
+	for _, c := range cases {
+		if got := bi.StoreWithOverflow(c.key, c.value); got != c.want {
+			t.Errorf("StoreWithOverflow(%q, %d): got %t; want %t", c.key, c.value, got, c.want)
+		}
+	}
+	want := map[string]int{"a": 3, "b": 2, "e": 1}
+	if ok, details := AssertEqual(bi, want); !ok {
+		t.Errorf("got unexpected pairs:\n%s", details)
+	}
+	wantOverflow := []Pair[string, int]{{"c", 1}, {"d", 2}}
+	if got := bi.Overflow(); !slices.Equal(got, wantOverflow) {
+		t.Errorf("got overflow %v; want %v", got, wantOverflow)
+	}
+}
+
+func TestThatOverflowIsEmptyWithoutCollisions(t *testing.T) {
+	bi := New[string, int]()
+	bi.StoreWithOverflow("a", 1)
+	bi.Store("b", 1) // plain Store evicts a:1
+	if got := bi.Overflow(); got != nil {
+		t.Errorf("got overflow %v; want nil", got)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2020 Julien Cretel (jub0bs). All rights reserved.
+// Use of this source code is governed by a BSD 3-clause
+// license that can be found in the LICENSE file.
+
+package bimap
+
+// Inversions returns the number of inversions of bi viewed as a
+// permutation, i.e. the number of pairs of keys i < j such that
+// i's value is greater than j's value.
+// The complexity is O(n log n), where n is the size of bi.
+func Inversions(bi *Bimap[int, int]) int {
+	keys := sortedKeys(bi)
+	values := make([]int, len(keys))
+	for i, k := range keys {
+		values[i], _ = bi.LoadValue(k)
+	}
+	return countInversions(values, make([]int, len(values)))
+}
+
+// countInversions sorts s, using buf (of the same length as s) as
+// scratch space, and returns the number of inversions it removed.
+func countInversions(s, buf []int) int {
+	if len(s) < 2 {
+		return 0
+	}
+	mid := len(s) / 2
+	n := countInversions(s[:mid], buf[:mid]) + countInversions(s[mid:], buf[mid:])
+	i, j, k := 0, mid, 0
+	for i < mid && j < len(s) {
+		if s[j] < s[i] {
+			n += mid - i // s[j] is smaller than all of s[i:mid]
+			buf[k] = s[j]
+			j++
+		} else {
+			buf[k] = s[i]
+			i++
+		}
+		k++
+	}
+	k += copy(buf[k:], s[i:mid])
+	copy(buf[k:], s[j:])
+	copy(s, buf)
+	return n
+}
+
+// FixedPoints returns the keys of bi that are associated with
+// themselves, in unspecified order.
+func FixedPoints[T comparable](bi *Bimap[T, T]) []T {
+	var points []T
+	for k, v := range bi.fwd().Range {
+		if k == v {
+			points = append(points, k)
+		}
+	}
+	return points
+}
+
+// WouldRemainPermutation reports whether, after a successful call to
+// bi.Store(k, v), the set of keys of bi would equal its set of
+// values, as it does for a permutation of some domain. It does not
+// modify bi.
+// The complexity is O(n), where n is the size of bi.
+func WouldRemainPermutation[T comparable](bi *Bimap[T, T], k, v T) bool {
+	keys := bi.KeySet()
+	values := bi.ValueSet()
+	if old, exists := bi.LoadValue(k); exists { // evicted by Store
+		delete(keys, k)
+		delete(values, old)
+	}
+	if old, exists := bi.LoadKey(v); exists { // evicted by Store
+		delete(keys, old)
+		delete(values, v)
+	}
+	keys[k] = struct{}{}
+	values[v] = struct{}{}
+	if len(keys) != len(values) {
+		return false
+	}
+	for t := range keys {
+		if !contains(values, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// Cycles decomposes bi, viewed as a permutation, into its disjoint
+// cycles: it returns one new Bimap per cycle, containing the
+// key-value pairs that form that cycle. A key associated with itself
+// forms a cycle of its own. Pairs that do not lie on any cycle, as
+// happens if bi is not a permutation of its keys, are left out.
+// The order of the cycles is unspecified.
+// The complexity is O(n), where n is the size of bi.
+func Cycles[T comparable](bi *Bimap[T, T]) []*Bimap[T, T] {
+	var cycles []*Bimap[T, T]
+	visited := make(map[T]struct{}, bi.Size())
+	for start := range bi.fwd().Range {
+		if contains(visited, start) {
+			continue
+		}
+		var closed bool
+		for x := start; ; {
+			visited[x] = struct{}{}
+			next, ok := bi.LoadValue(x)
+			if !ok || contains(visited, next) {
+				closed = ok && next == start
+				break
+			}
+			x = next
+		}
+		if !closed {
+			continue
+		}
+		cycle := New[T, T]()
+		for x := start; ; {
+			next, _ := bi.LoadValue(x)
+			cycle.Store(x, next)
+			if next == start {
+				break
+			}
+			x = next
+		}
+		cycles = append(cycles, cycle)
+	}
+	return cycles
+}
+
+// PermutationOrder returns the order of bi viewed as a permutation,
+// i.e. the smallest positive number k of times that bi must be
+// applied to yield the identity; it is the least common multiple of
+// the lengths of bi's cycles (see Cycles). The order of an empty
+// Bimap is 1. PermutationOrder returns 0 if bi is not a permutation
+// of its keys, i.e. if bi's keys differ from its values.
+func PermutationOrder[T comparable](bi *Bimap[T, T]) int {
+	order, n := 1, 0
+	for _, c := range Cycles(bi) {
+		size := c.Size()
+		order = order / gcd(order, size) * size
+		n += size
+	}
+	if n != bi.Size() {
+		return 0 // some pairs lie on open chains
+	}
+	return order
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
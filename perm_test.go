@@ -0,0 +1,151 @@
+package bimap
+
+import (
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestInversions(t *testing.T) {
+	cases := []struct {
+		desc   string
+		values []int // values[i] is the value of key i
+		want   int
+	}{
+		{"empty", nil, 0},
+		{"identity", []int{0, 1, 2, 3, 4}, 0},
+		{"reversed", []int{4, 3, 2, 1, 0}, 10},
+		{"single transposition", []int{0, 2, 1, 3}, 1},
+		{"mixed", []int{2, 4, 1, 3, 0}, 7},
+	}
+	for _, c := range cases {
+		bi := New[int, int]()
+		for k, v := range c.values {
+			bi.Store(k, v)
+		}
+		if got := Inversions(bi); got != c.want {
+			t.Errorf("%s: got %d; want %d", c.desc, got, c.want)
+		}
+	}
+}
+
+func TestFixedPointsOfTheIdentity(t *testing.T) {
+	bi := New[int, int]()
+	for i := 0; i < 4; i++ {
+		bi.Store(i, i)
+	}
+	got := FixedPoints(bi)
+	sort.Ints(got)
+	if want := []int{0, 1, 2, 3}; !slices.Equal(got, want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+}
+
+func TestFixedPointsOfADerangement(t *testing.T) {
+	bi := New[int, int]()
+	for i := 0; i < 4; i++ {
+		bi.Store(i, (i+1)%4)
+	}
+	if got := FixedPoints(bi); len(got) != 0 {
+		t.Errorf("got %v; want none", got)
+	}
+}
+
+func TestWouldRemainPermutation(t *testing.T) {
+	bi := New[int, int]() // the cycle 0 -> 1 -> 2 -> 0
+	bi.Store(0, 1)
+	bi.Store(1, 2)
+	bi.Store(2, 0)
+	cases := []struct {
+		desc string
+		k, v int
+		want bool
+	}{
+		{desc: "existing pair", k: 0, v: 1, want: true},
+		{desc: "new fixed point", k: 3, v: 3, want: true},
+		{desc: "new key only", k: 3, v: 4, want: false},
+		{desc: "value moved to another key", k: 0, v: 2, want: true},
+		{desc: "value outside the domain", k: 0, v: 3, want: false},
+		{desc: "value moved to a new key", k: 3, v: 0, want: false},
+	}
+	for _, c := range cases {
+		if got := WouldRemainPermutation(bi, c.k, c.v); got != c.want {
+			t.Errorf("%s: got %t; want %t", c.desc, got, c.want)
+		}
+	}
+	if got := bi.Size(); got != 3 {
+		t.Errorf("got size %d; want %d", got, 3)
+	}
+}
+
+func TestCycles(t *testing.T) {
+	bi := New[int, int]()
+	bi.Store(0, 2) // 0 -> 2 -> 4 -> 0
+	bi.Store(2, 4)
+	bi.Store(4, 0)
+	bi.Store(1, 3) // 1 -> 3 -> 1
+	bi.Store(3, 1)
+	bi.Store(5, 5) // 5 -> 5
+	cycles := Cycles(bi)
+	var sizes []int
+	union := New[int, int]()
+	for _, c := range cycles {
+		sizes = append(sizes, c.Size())
+		for k, v := range c.fwd().Range {
+			if _, exists := union.LoadValue(k); exists {
+				t.Errorf("key %d appears in several cycles", k)
+			}
+			union.Store(k, v)
+		}
+	}
+	sort.Ints(sizes)
+	if want := []int{1, 2, 3}; !slices.Equal(sizes, want) {
+		t.Errorf("got cycle sizes %v; want %v", sizes, want)
+	}
+	if !union.Equal(bi) {
+		t.Errorf("got union %v; want %v", union, bi)
+	}
+}
+
+func TestThatCyclesLeavesOutOpenChains(t *testing.T) {
+	bi := New[int, int]()
+	bi.Store(0, 1) // 0 -> 1 -> 2
+	bi.Store(1, 2)
+	bi.Store(7, 8) // 7 -> 8 -> 7
+	bi.Store(8, 7)
+	cycles := Cycles(bi)
+	if len(cycles) != 1 {
+		t.Fatalf("got %d cycles; want %d", len(cycles), 1)
+	}
+	if want := MustFromMap(map[int]int{7: 8, 8: 7}); !cycles[0].Equal(want) {
+		t.Errorf("got %v; want %v", cycles[0], want)
+	}
+}
+
+func TestPermutationOrder(t *testing.T) {
+	cases := []struct {
+		desc string
+		m    map[int]int
+		want int
+	}{
+		{desc: "empty", m: nil, want: 1},
+		{desc: "identity", m: map[int]int{0: 0, 1: 1, 2: 2}, want: 1},
+		{desc: "single 3-cycle", m: map[int]int{0: 1, 1: 2, 2: 0}, want: 3},
+		{
+			desc: "disjoint 2-, 3-, and 4-cycles",
+			m: map[int]int{
+				0: 1, 1: 0,
+				2: 3, 3: 4, 4: 2,
+				5: 6, 6: 7, 7: 8, 8: 5,
+			},
+			want: 12,
+		},
+		{desc: "not a permutation", m: map[int]int{0: 1, 1: 2}, want: 0},
+	}
+	for _, c := range cases {
+		if got := PermutationOrder(MustFromMap(c.m)); got != c.want {
+			t.Errorf("%s: got %d; want %d", c.desc, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2020 Julien Cretel (jub0bs). All rights reserved.
+// Use of this source code is governed by a BSD 3-clause
+// license that can be found in the LICENSE file.
+
+package bimap
+
+import "strings"
+
+// NewBounded returns a new, empty Bimap that rejects keys longer
+// than maxKey bytes and values longer than maxVal bytes. A zero or
+// negative limit means no limit.
+func NewBounded(maxKey, maxVal int) *Bimap[string, string] {
+	return NewValidated(maxLen(maxKey), maxLen(maxVal))
+}
+
+// maxLen returns a function that reports whether a string is at
+// most n bytes long, or nil if n is zero or negative.
+func maxLen(n int) func(string) bool {
+	if n <= 0 {
+		return nil
+	}
+	return func(s string) bool { return len(s) <= n }
+}
+
+// LoadValueFold returns the value stored in bi for k or, if k is
+// absent from bi, for a key equal to k under Unicode case-folding
+// (see strings.EqualFold); if several such keys exist, which one is
+// used is unspecified. Whereas the exact lookup runs in constant
+// time, the fallback runs in time linear in the size of bi.
+// The ok result indicates whether a value was found.
+func LoadValueFold[V comparable](bi *Bimap[string, V], k string) (V, bool) {
+	if v, ok := bi.LoadValue(k); ok {
+		return v, true
+	}
+	for k2, v := range bi.fwd().Range {
+		if strings.EqualFold(k, k2) {
+			return v, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// LoadValueTrimmed returns the value stored in bi for k or, if k is
+// absent from bi, for k stripped of its leading and trailing white
+// space (see strings.TrimSpace). The exact lookup takes precedence:
+// the trimmed key is only tried if k itself is absent. Both lookups
+// run in constant time.
+// The ok result indicates whether a value was found.
+func LoadValueTrimmed[V comparable](bi *Bimap[string, V], k string) (V, bool) {
+	if v, ok := bi.LoadValue(k); ok {
+		return v, true
+	}
+	return bi.LoadValue(strings.TrimSpace(k))
+}
@@ -0,0 +1,74 @@
+package bimap
+
+import "testing"
+
+func TestNewBounded(t *testing.T) {
+	cases := []struct {
+		desc           string
+		maxKey, maxVal int
+		key, value     string
+		want           bool
+	}{
+		{desc: "both below", maxKey: 3, maxVal: 4, key: "ab", value: "abc", want: true},
+		{desc: "both at", maxKey: 3, maxVal: 4, key: "abc", value: "abcd", want: true},
+		{desc: "key above", maxKey: 3, maxVal: 4, key: "abcd", value: "abcd", want: false},
+		{desc: "value above", maxKey: 3, maxVal: 4, key: "abc", value: "abcde", want: false},
+		{desc: "unlimited keys", maxKey: 0, maxVal: 4, key: "abcdefgh", value: "abcd", want: true},
+		{desc: "unlimited values", maxKey: 3, maxVal: 0, key: "abc", value: "abcdefgh", want: true},
+	}
+	for _, c := range cases {
+		bi := NewBounded(c.maxKey, c.maxVal)
+		if got := bi.Store(c.key, c.value); got != c.want {
+			t.Errorf("%s: got %t; want %t", c.desc, got, c.want)
+		}
+		if !c.want && bi.Size() != 0 {
+			t.Errorf("%s: got size %d; want %d", c.desc, bi.Size(), 0)
+		}
+	}
+}
+
+func TestLoadValueFold(t *testing.T) {
+	bi := New[string, int]()
+	bi.Store("Go", 1)
+	bi.Store("go", 2)
+	bi.Store("Rust", 3)
+	cases := []struct {
+		key  string
+		want int
+		ok   bool
+	}{
+		{"Go", 1, true},
+		{"go", 2, true},
+		{"RUST", 3, true},
+		{"Zig", 0, false},
+	}
+	for _, c := range cases {
+		got, ok := LoadValueFold(bi, c.key)
+		if got != c.want || ok != c.ok {
+			t.Errorf("LoadValueFold(bi, %q): got %d, %t; want %d, %t", c.key, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestLoadValueTrimmed(t *testing.T) {
+	bi := New[string, int]()
+	bi.Store("go", 1)
+	bi.Store(" go", 2)
+	cases := []struct {
+		key  string
+		want int
+		ok   bool
+	}{
+		{"go", 1, true},
+		{" go", 2, true}, // exact match takes precedence
+		{"\tgo \n", 1, true},
+		{"g o", 0, false},
+		{"  ", 0, false},
+	}
+	for _, c := range cases {
+		got, ok := LoadValueTrimmed(bi, c.key)
+		if got != c.want || ok != c.ok {
+			t.Errorf("LoadValueTrimmed(bi, %q): got %d, %t; want %d, %t", c.key, got, ok, c.want, c.ok)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2020 Julien Cretel (jub0bs). All rights reserved.
+// Use of this source code is governed by a BSD 3-clause
+// license that can be found in the LICENSE file.
+
+package bimap
+
+// SoftDeleteByKey deletes the key-value pair involving the given
+// key, as DeleteByKey does, but keeps a tombstone of that pair so
+// that it can later be restored (see Restore). Tombstoned pairs are
+// invisible to every other method. SoftDeleteByKey reports whether
+// the key was present in the Bimap. Soft-deleting a key replaces
+// any tombstone previously kept for it.
+func (bi *Bimap[K, V]) SoftDeleteByKey(k K) bool {
+	v, exists := bi.fwd().Load(k)
+	if !exists {
+		return false
+	}
+	bi.DeleteByKey(k)
+	if bi.tombs == nil {
+		bi.tombs = make(map[K]V)
+	}
+	bi.tombs[k] = v
+	return true
+}
+
+// Restore stores back the key-value pair that the tombstone kept for
+// the given key holds, removes that tombstone, and reports whether
+// it did. Unlike Store, Restore never evicts a pair: it fails, and
+// keeps the tombstone, if the key or the value has since been stored
+// in the Bimap or if Store would fail. Restore also fails if no
+// tombstone is kept for the key.
+func (bi *Bimap[K, V]) Restore(k K) bool {
+	if bi == nil {
+		return false
+	}
+	v, exists := bi.tombs[k]
+	if !exists {
+		return false
+	}
+	if _, exists := bi.fwd().Load(k); exists {
+		return false
+	}
+	if _, exists := bi.inv().Load(v); exists {
+		return false
+	}
+	if !bi.Store(k, v) {
+		return false
+	}
+	delete(bi.tombs, k)
+	return true
+}
+
+// PurgeTombstones removes all the tombstones kept by the Bimap, thus
+// making all soft deletions (see SoftDeleteByKey) final, and returns
+// the number of tombstones removed.
+func (bi *Bimap[K, V]) PurgeTombstones() int {
+	if bi == nil {
+		return 0
+	}
+	n := len(bi.tombs)
+	bi.tombs = nil
+	return n
+}
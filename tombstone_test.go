@@ -0,0 +1,68 @@
+package bimap
+
+import "testing"
+
+func TestSoftDeleteAndRestore(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	if !bi.SoftDeleteByKey(1) {
+		t.Fatalf("SoftDeleteByKey(1): got %t; want %t", false, true)
+	}
+	if bi.SoftDeleteByKey(3) {
+		t.Errorf("SoftDeleteByKey(3): got %t; want %t", true, false)
+	}
+	if v, ok := bi.LoadValue(1); ok {
+		t.Errorf("LoadValue(1): got %q, %t; want %q, %t", v, ok, "", false)
+	}
+	if k, ok := bi.LoadKey("one"); ok {
+		t.Errorf("LoadKey(%q): got %d, %t; want %d, %t", "one", k, ok, 0, false)
+	}
+	if got := bi.Size(); got != 1 {
+		t.Errorf("Size: got %d; want %d", got, 1)
+	}
+	if !bi.Restore(1) {
+		t.Fatalf("Restore(1): got %t; want %t", false, true)
+	}
+	if !bi.ContainsPair(1, "one") || bi.Size() != 2 {
+		t.Errorf("got %v; want Bimap[1:one 2:two]", bi)
+	}
+	if bi.Restore(1) {
+		t.Errorf("second Restore(1): got %t; want %t", true, false)
+	}
+}
+
+func TestThatRestoreFailsIfTheValueWasStoredAgain(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.SoftDeleteByKey(1)
+	bi.Store(2, "one")
+	if bi.Restore(1) {
+		t.Errorf("got %t; want %t", true, false)
+	}
+	if k, ok := bi.LoadKey("one"); k != 2 || !ok {
+		t.Errorf("LoadKey(%q): got %d, %t; want %d, %t", "one", k, ok, 2, true)
+	}
+	bi.DeleteByKey(2)
+	if !bi.Restore(1) {
+		t.Errorf("after deleting the conflict: got %t; want %t", false, true)
+	}
+}
+
+func TestPurgeTombstones(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	bi.Store(3, "three")
+	bi.SoftDeleteByKey(1)
+	bi.SoftDeleteByKey(2)
+	if got := bi.PurgeTombstones(); got != 2 {
+		t.Errorf("got %d; want %d", got, 2)
+	}
+	if bi.Restore(1) || bi.Restore(2) {
+		t.Error("got successful Restore after PurgeTombstones")
+	}
+	if got := bi.Size(); got != 1 {
+		t.Errorf("Size: got %d; want %d", got, 1)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2020 Julien Cretel (jub0bs). All rights reserved.
+// Use of this source code is governed by a BSD 3-clause
+// license that can be found in the LICENSE file.
+
+package bimap
+
+// A Tx accumulates stores and deletes to be applied to a Bimap
+// all at once, or not at all.
+type Tx[K, V comparable] struct {
+	bi  *Bimap[K, V]
+	ops []txOp[K, V]
+}
+
+type txOp[K, V comparable] struct {
+	pair   Pair[K, V]
+	delete bool // whether the operation deletes pair.Key
+}
+
+// Tx returns a new, empty transaction on the Bimap. Tx panics if
+// bi is nil.
+func (bi *Bimap[K, V]) Tx() *Tx[K, V] {
+	bi.mustBeNonNil("Tx")
+	return &Tx[K, V]{bi: bi}
+}
+
+// Store records the storing of a key-value pair in the transaction
+// and returns the transaction.
+func (tx *Tx[K, V]) Store(key K, value V) *Tx[K, V] {
+	tx.ops = append(tx.ops, txOp[K, V]{pair: Pair[K, V]{key, value}})
+	return tx
+}
+
+// Delete records the deletion of the key-value pair involving the
+// given key in the transaction and returns the transaction.
+func (tx *Tx[K, V]) Delete(key K) *Tx[K, V] {
+	tx.ops = append(tx.ops, txOp[K, V]{pair: Pair[K, V]{Key: key}, delete: true})
+	return tx
+}
+
+// Commit applies the operations recorded in the transaction, in
+// order, and returns whether or not it did. Unlike Bimap.Store,
+// a store recorded in a transaction must not evict any pair:
+// Commit applies no operation at all if any recorded store would
+// evict a pair or would fail. Deletes of absent keys are ignored.
+// In either case, Commit discards the recorded operations.
+func (tx *Tx[K, V]) Commit() bool {
+	ops := tx.ops
+	tx.ops = nil
+	if !tx.validate(ops) {
+		return false
+	}
+	for _, op := range ops {
+		if op.delete {
+			tx.bi.DeleteByKey(op.pair.Key)
+		} else {
+			tx.bi.Store(op.pair.Key, op.pair.Value)
+		}
+	}
+	return true
+}
+
+// validate reports whether ops can be applied to the Bimap without
+// evicting any pair and without any store failing.
+func (tx *Tx[K, V]) validate(ops []txOp[K, V]) bool {
+	bi := tx.bi
+	// Changes are recorded in an overlay rather than in a copy of bi.
+	forward := make(map[K]txEntry[V])
+	inverse := make(map[V]txEntry[K])
+	loadValue := func(k K) (V, bool) {
+		if e, ok := forward[k]; ok {
+			return e.val, e.ok
+		}
+		return bi.LoadValue(k)
+	}
+	loadKey := func(v V) (K, bool) {
+		if e, ok := inverse[v]; ok {
+			return e.val, e.ok
+		}
+		return bi.LoadKey(v)
+	}
+	size := bi.Size()
+	for _, op := range ops {
+		k, v := op.pair.Key, op.pair.Value
+		if op.delete {
+			if v, exists := loadValue(k); exists {
+				forward[k] = txEntry[V]{}
+				inverse[v] = txEntry[K]{}
+				size--
+			}
+			continue
+		}
+		if !bi.accepts(k, v) {
+			return false
+		}
+		v2, keyExists := loadValue(k)
+		_, valueExists := loadKey(v)
+		if keyExists && v2 == v {
+			continue // the pair is already present
+		}
+		if keyExists || valueExists {
+			return false
+		}
+		if bi.maxSize > 0 && size >= bi.maxSize {
+			return false
+		}
+		forward[k] = txEntry[V]{v, true}
+		inverse[v] = txEntry[K]{k, true}
+		size++
+	}
+	return true
+}
+
+// txEntry is an entry of a transaction's overlay; an entry whose
+// ok field is false denotes a deletion.
+type txEntry[T comparable] struct {
+	val T
+	ok  bool
+}
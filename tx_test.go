@@ -0,0 +1,69 @@
+package bimap
+
+import "testing"
+
+func TestCommittingATransaction(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	ok := bi.Tx().
+		Delete(1).
+		Store(3, "one"). // "one" was freed by the delete
+		Store(2, "two"). // already present
+		Store(4, "four").
+		Delete(5). // absent
+		Commit()
+	if !ok {
+		t.Fatalf("got %t; want %t", ok, true)
+	}
+	want := New[int, string]()
+	want.Store(2, "two")
+	want.Store(3, "one")
+	want.Store(4, "four")
+	if !bi.Equal(want) || !CheckRoundTrip(bi) {
+		t.Errorf("got %v; want %v", bi, want)
+	}
+}
+
+func TestThatAConflictingTransactionRollsBack(t *testing.T) {
+	cases := []struct {
+		desc string
+		tx   func(*Tx[int, string]) *Tx[int, string]
+	}{
+		{
+			desc: "store evicting an existing key",
+			tx: func(tx *Tx[int, string]) *Tx[int, string] {
+				return tx.Store(3, "three").Store(1, "uno")
+			},
+		}, {
+			desc: "store evicting an existing value",
+			tx: func(tx *Tx[int, string]) *Tx[int, string] {
+				return tx.Delete(1).Store(3, "two")
+			},
+		}, {
+			desc: "store evicting a pair stored earlier in the transaction",
+			tx: func(tx *Tx[int, string]) *Tx[int, string] {
+				return tx.Store(3, "three").Store(4, "three")
+			},
+		}, {
+			desc: "rejected store",
+			tx: func(tx *Tx[int, string]) *Tx[int, string] {
+				return tx.Store(3, "three").Store(0, "")
+			},
+		},
+	}
+	for _, c := range cases {
+		bi := NewNonZero[int, string]()
+		bi.Store(1, "one")
+		bi.Store(2, "two")
+		want := New[int, string]()
+		want.Store(1, "one")
+		want.Store(2, "two")
+		if ok := c.tx(bi.Tx()).Commit(); ok {
+			t.Errorf("%s: got %t; want %t", c.desc, ok, false)
+		}
+		if !bi.Equal(want) {
+			t.Errorf("%s: got %v; want %v", c.desc, bi, want)
+		}
+	}
+}
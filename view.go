@@ -0,0 +1,67 @@
+// Copyright 2020 Julien Cretel (jub0bs). All rights reserved.
+// Use of this source code is governed by a BSD 3-clause
+// license that can be found in the LICENSE file.
+
+package bimap
+
+// A Scope is a live, read-only view of a Bimap restricted to
+// some keys. It reflects subsequent changes to the underlying
+// Bimap.
+type Scope[K, V comparable] struct {
+	parent *Bimap[K, V]
+	keys   map[K]struct{}
+}
+
+// Scoped returns a Scope of the Bimap restricted to the given keys.
+func (bi *Bimap[K, V]) Scoped(keys ...K) *Scope[K, V] {
+	set := make(map[K]struct{}, len(keys))
+	for _, k := range keys {
+		set[k] = struct{}{}
+	}
+	return &Scope[K, V]{parent: bi, keys: set}
+}
+
+// LoadValue returns the value stored in the underlying Bimap for a
+// key, or the zero value of the V type if no value is present or
+// if the key is outside the Scope.
+// The ok result indicates whether the key was found in the Scope.
+func (s *Scope[K, V]) LoadValue(k K) (V, bool) {
+	if !contains(s.keys, k) {
+		var zero V
+		return zero, false
+	}
+	return s.parent.LoadValue(k)
+}
+
+// A ForwardView is a live, read-only view of the key-to-value
+// direction of a Bimap. It reads the Bimap's backings directly,
+// without copying them, and reflects subsequent changes to the
+// Bimap. Like the Bimap itself, a ForwardView is not safe for use
+// while the Bimap is being modified concurrently.
+type ForwardView[K, V comparable] struct {
+	parent *Bimap[K, V]
+}
+
+// ForwardView returns a ForwardView of the Bimap.
+func (bi *Bimap[K, V]) ForwardView() *ForwardView[K, V] {
+	return &ForwardView[K, V]{parent: bi}
+}
+
+// Get returns the value stored in the underlying Bimap for a key,
+// or the zero value of the V type if no value is present.
+// The ok result indicates whether the key was found.
+func (fv *ForwardView[K, V]) Get(k K) (V, bool) {
+	return fv.parent.fwd().Load(k)
+}
+
+// Len returns the number of key-value pairs in the underlying Bimap.
+func (fv *ForwardView[K, V]) Len() int {
+	return fv.parent.fwd().Len()
+}
+
+// Range calls f sequentially for each key-value pair in the
+// underlying Bimap, in unspecified order. If f returns false,
+// Range stops the iteration.
+func (fv *ForwardView[K, V]) Range(f func(K, V) bool) {
+	fv.parent.fwd().Range(f)
+}
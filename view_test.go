@@ -0,0 +1,76 @@
+package bimap
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestThatAScopeOnlySeesItsKeys(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	bi.Store(2, "two")
+	s := bi.Scoped(1, 3)
+	if v, ok := s.LoadValue(1); !ok || v != "one" {
+		t.Errorf("got %q, %t; want %q, %t", v, ok, "one", true)
+	}
+	if v, ok := s.LoadValue(2); ok {
+		t.Errorf("got %q, %t; want %q, %t", v, ok, "", false)
+	}
+	if v, ok := s.LoadValue(3); ok {
+		t.Errorf("got %q, %t; want %q, %t", v, ok, "", false)
+	}
+}
+
+func TestThatAScopeReflectsChangesToItsParent(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	s := bi.Scoped(1, 3)
+	bi.Store(3, "three")
+	bi.Store(1, "uno")
+	if v, ok := s.LoadValue(3); !ok || v != "three" {
+		t.Errorf("got %q, %t; want %q, %t", v, ok, "three", true)
+	}
+	if v, ok := s.LoadValue(1); !ok || v != "uno" {
+		t.Errorf("got %q, %t; want %q, %t", v, ok, "uno", true)
+	}
+	bi.DeleteByKey(3)
+	if v, ok := s.LoadValue(3); ok {
+		t.Errorf("got %q, %t; want %q, %t", v, ok, "", false)
+	}
+}
+
+func TestThatAForwardViewReflectsTheForwardMapping(t *testing.T) {
+	bi := New[int, string]()
+	bi.Store(1, "one")
+	fv := bi.ForwardView()
+	bi.Store(2, "two")
+	bi.Store(1, "uno")
+	if v, ok := fv.Get(1); !ok || v != "uno" {
+		t.Errorf("got %q, %t; want %q, %t", v, ok, "uno", true)
+	}
+	if v, ok := fv.Get(3); ok {
+		t.Errorf("got %q, %t; want %q, %t", v, ok, "", false)
+	}
+	if got := fv.Len(); got != 2 {
+		t.Errorf("got %d; want %d", got, 2)
+	}
+	got := make(map[int]string)
+	for k, v := range fv.Range {
+		got[k] = v
+	}
+	if ok, details := AssertEqual(bi, got); !ok {
+		t.Errorf("got unexpected pairs:\n%s", details)
+	}
+}
+
+func TestThatAForwardViewExposesNoMutationPath(t *testing.T) {
+	typ := reflect.TypeOf(new(Bimap[int, string]).ForwardView())
+	var got []string
+	for i := 0; i < typ.NumMethod(); i++ {
+		got = append(got, typ.Method(i).Name)
+	}
+	want := []string{"Get", "Len", "Range"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got methods %v; want %v", got, want)
+	}
+}